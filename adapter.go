@@ -105,7 +105,7 @@ func (adapter *Decoder) InputOffset() int64 {
 func (adapter *Decoder) UseNumber() {
 	cfg := adapter.iter.cfg.configBeforeFrozen
 	cfg.UseNumber = true
-	adapter.iter.cfg = cfg.frozeWithCacheReuse(adapter.iter.cfg.extraExtensions)
+	adapter.iter.cfg = adapter.iter.cfg.deriveWithCacheReuse(cfg)
 }
 
 // DisallowUnknownFields causes the Decoder to return an error when the destination
@@ -114,7 +114,7 @@ func (adapter *Decoder) UseNumber() {
 func (adapter *Decoder) DisallowUnknownFields() {
 	cfg := adapter.iter.cfg.configBeforeFrozen
 	cfg.DisallowUnknownFields = true
-	adapter.iter.cfg = cfg.frozeWithCacheReuse(adapter.iter.cfg.extraExtensions)
+	adapter.iter.cfg = adapter.iter.cfg.deriveWithCacheReuse(cfg)
 }
 
 // NewEncoder same as json.NewEncoder
@@ -139,14 +139,14 @@ func (adapter *Encoder) Encode(val interface{}) error {
 func (adapter *Encoder) SetIndent(prefix, indent string) {
 	config := adapter.stream.cfg.configBeforeFrozen
 	config.IndentionStep = len(indent)
-	adapter.stream.cfg = config.frozeWithCacheReuse(adapter.stream.cfg.extraExtensions)
+	adapter.stream.cfg = adapter.stream.cfg.deriveWithCacheReuse(config)
 }
 
 // SetEscapeHTML escape html by default, set to false to disable
 func (adapter *Encoder) SetEscapeHTML(escapeHTML bool) {
 	config := adapter.stream.cfg.configBeforeFrozen
 	config.EscapeHTML = escapeHTML
-	adapter.stream.cfg = config.frozeWithCacheReuse(adapter.stream.cfg.extraExtensions)
+	adapter.stream.cfg = adapter.stream.cfg.deriveWithCacheReuse(config)
 }
 
 // Valid reports whether data is a valid JSON encoding.
@@ -227,3 +227,99 @@ func Test_OnlyTaggedField(t *testing.T) {
 		"j": "j",
 	}, m)
 }
+
+func Test_serialization_groups(t *testing.T) {
+	should := require.New(t)
+	type User struct {
+		ID       int    `json:"id"`
+		Name     string `json:"name" groups:"public,admin"`
+		Email    string `json:"email" groups:"admin"`
+		Password string `json:"password" groups:"internal"`
+	}
+	user := User{1, "a", "a@b", "secret"}
+	output, err := jsoniter.MarshalToString(user)
+	should.NoError(err)
+	should.Equal(`{"id":1,"name":"a","email":"a@b","password":"secret"}`, output)
+	output, err = jsoniter.WithGroups(jsoniter.ConfigDefault, "public").MarshalToString(user)
+	should.NoError(err)
+	should.Equal(`{"id":1,"name":"a"}`, output)
+	admin := jsoniter.WithGroups(jsoniter.ConfigDefault, "internal", "admin")
+	should.True(admin == jsoniter.WithGroups(jsoniter.ConfigDefault, "admin", "internal"))
+	output, err = admin.MarshalToString(user)
+	should.NoError(err)
+	should.Equal(`{"id":1,"name":"a","email":"a@b","password":"secret"}`, output)
+
+	input := `{"id":1,"name":"a","email":"a@b","password":"secret"}`
+	var decoded User
+	should.NoError(jsoniter.Config{Groups: "public"}.Froze().UnmarshalFromString(input, &decoded))
+	should.Equal(user, decoded)
+	decoded = User{}
+	should.NoError(jsoniter.Config{Groups: "public", DecodeByGroups: true}.Froze().UnmarshalFromString(input, &decoded))
+	should.Equal(User{ID: 1, Name: "a"}, decoded)
+}
+
+func Test_redaction(t *testing.T) {
+	should := require.New(t)
+	type Credentials struct {
+		Token string `json:"token,redact"`
+	}
+	type Account struct {
+		Credentials
+		Name  string `json:"name"`
+		Email string `json:"email,omitempty,redact"`
+	}
+	account := Account{Credentials{"secret"}, "a", "a@b"}
+	output, err := jsoniter.MarshalToString(account)
+	should.NoError(err)
+	should.Equal(`{"token":"secret","name":"a","email":"a@b"}`, output)
+	output, err = jsoniter.Config{Redaction: jsoniter.RedactMask}.Froze().MarshalToString(account)
+	should.NoError(err)
+	should.Equal(`{"token":"***","name":"a","email":"***"}`, output)
+	output, err = jsoniter.Config{Redaction: jsoniter.RedactOmit}.Froze().MarshalToString(account)
+	should.NoError(err)
+	should.Equal(`{"name":"a"}`, output)
+	output, err = jsoniter.Config{Redaction: jsoniter.RedactHash}.Froze().MarshalToString(Account{Name: "a"})
+	should.NoError(err)
+	// sha256 of ""
+	should.Equal(`{"token":"12ae32cb1ec02d01eda3581b127c1fee3b0dc53572ed6baf239721a03d82e126","name":"a"}`, output)
+
+	stream := jsoniter.ConfigDefault.BorrowStream(nil)
+	stream.SetRedaction(jsoniter.RedactMask)
+	stream.WriteVal(account)
+	should.Equal(`{"token":"***","name":"a","email":"***"}`, string(stream.Buffer()))
+	jsoniter.ConfigDefault.ReturnStream(stream)
+	stream = jsoniter.ConfigDefault.BorrowStream(nil)
+	defer jsoniter.ConfigDefault.ReturnStream(stream)
+	stream.WriteVal(account)
+	should.Equal(`{"token":"secret","name":"a","email":"a@b"}`, string(stream.Buffer()))
+}
+
+func Test_redaction_in_sorted_map(t *testing.T) {
+	should := require.New(t)
+	type Credentials struct {
+		Token string `json:"token,redact"`
+	}
+	api := jsoniter.Config{SortMapKeys: true}.Froze()
+	stream := api.BorrowStream(nil)
+	defer api.ReturnStream(stream)
+	stream.SetRedaction(jsoniter.RedactMask)
+	stream.WriteVal(map[string]Credentials{"b": {"hunter2"}, "a": {"hunter2"}})
+	should.Equal(`{"a":{"token":"***"},"b":{"token":"***"}}`, string(stream.Buffer()))
+
+	output, err := jsoniter.Config{SortMapKeys: true, Redaction: jsoniter.RedactHash}.Froze().
+		MarshalIndent(map[string]Credentials{"a": {}}, "", "  ")
+	should.NoError(err)
+	should.Equal("{\n  \"a\": {\n    \"token\": \"12ae32cb1ec02d01eda3581b127c1fee3b0dc53572ed6baf239721a03d82e126\"\n  }\n}", string(output))
+}
+
+func Test_redaction_hash_key(t *testing.T) {
+	should := require.New(t)
+	type Credentials struct {
+		Token string `json:"token,redact"`
+	}
+	output, err := jsoniter.Config{Redaction: jsoniter.RedactHash, RedactionKey: "key"}.Froze().
+		MarshalToString(Credentials{})
+	should.NoError(err)
+	// HMAC-SHA256 of "" keyed by "key"
+	should.Equal(`{"token":"1a86a4386d21f6fe068d2c0e592fa29a957f9575f0ce8aaea831a7cfa0eeb0db"}`, output)
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/json-iterator/go"
 	"github.com/stretchr/testify/require"
 	"io/ioutil"
+	"strconv"
 	"testing"
 )
 
@@ -62,3 +63,95 @@ func Test_decoder_more(t *testing.T) {
 	decoder := jsoniter.NewDecoder(bytes.NewBufferString("abcde"))
 	should.True(decoder.More())
 }
+
+func Test_decode_with_projection(t *testing.T) {
+	should := require.New(t)
+	type Owner struct {
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	}
+	type Node struct {
+		ID     int               `json:"id"`
+		Name   string            `json:"name"`
+		Owner  *Owner            `json:"owner"`
+		Owners []Owner           `json:"owners"`
+		Extra  map[string]Owner  `json:"extra"`
+		Counts map[int]int       `json:"counts"`
+		Next   *Node             `json:"next"`
+		Raw    map[string]string `json:"raw"`
+	}
+	input := `{"id":1,"name":"a","owner":{"name":"b","email":"b@c"},"owners":[{"name":"c","email":"c@d"}],
+		"extra":{"x":{"name":"d","email":"d@e"},"y":{"name":"e"}},"counts":{"1":2},
+		"next":{"id":2,"name":"f","next":{"id":3}},"raw":{"k":"v"},"unknown":[1,2]}`
+	api := jsoniter.WithProjection(jsoniter.Config{DisallowUnknownFields: true}.Froze(),
+		"id", "owner.email", "owners.name", "extra.x.name", "extra.y", "counts", "next.next.id")
+	var node Node
+	should.NoError(api.UnmarshalFromString(input, &node))
+	should.Equal(Node{
+		ID:     1,
+		Owner:  &Owner{Email: "b@c"},
+		Owners: []Owner{{Name: "c"}},
+		Extra:  map[string]Owner{"x": {Name: "d"}, "y": {Name: "e"}},
+		Counts: map[int]int{1: 2},
+		Next:   &Node{Next: &Node{ID: 3}},
+	}, node)
+	should.Error(api.UnmarshalFromString(`{"id":"1"}`, &node))
+	should.NoError(api.UnmarshalFromString(`{"name":1}`, &node))
+	output, err := api.MarshalToString(Owner{"a", "b"})
+	should.NoError(err)
+	should.Equal(`{"name":"a","email":"b"}`, output)
+}
+
+func Test_decode_with_projection_of_int_map_and_alias(t *testing.T) {
+	should := require.New(t)
+	type Owner struct {
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	}
+	type Node struct {
+		M     map[int]Owner `json:"m"`
+		Owner Owner         `json:"owner,alias=user"`
+	}
+	var node Node
+	should.NoError(jsoniter.WithProjection(jsoniter.ConfigDefault, "m.1").
+		UnmarshalFromString(`{"m":{"1":{"name":"a","email":"b"}},"owner":{"name":"c"}}`, &node))
+	should.Equal(Node{M: map[int]Owner{1: {Name: "a", Email: "b"}}}, node)
+	node = Node{}
+	should.NoError(jsoniter.WithProjection(jsoniter.ConfigDefault, "user.email").
+		UnmarshalFromString(`{"user":{"name":"c","email":"d"}}`, &node))
+	should.Equal(Node{Owner: Owner{Email: "d"}}, node)
+}
+
+type projectedRecursion []projectedRecursion
+
+type projectedTree struct {
+	X     int                `json:"x"`
+	Y     int                `json:"y"`
+	Kids  []*projectedTree   `json:"kids"`
+	Other projectedRecursion `json:"r"`
+}
+
+func Test_decode_with_projection_of_recursive_type(t *testing.T) {
+	should := require.New(t)
+	var tree projectedTree
+	should.NoError(jsoniter.WithProjection(jsoniter.ConfigDefault, "r.x").
+		UnmarshalFromString(`{"x":1,"r":[[],[[]]]}`, &tree))
+	should.Equal(projectedTree{Other: projectedRecursion{{}, {{}}}}, tree)
+	tree = projectedTree{}
+	should.NoError(jsoniter.WithProjection(jsoniter.ConfigDefault, "kids.x,kids.kids.x,y").
+		UnmarshalFromString(`{"x":1,"y":2,"kids":[{"x":3,"y":4,"kids":[{"x":5,"y":6}]}]}`, &tree))
+	should.Equal(projectedTree{Y: 2, Kids: []*projectedTree{{X: 3, Kids: []*projectedTree{{X: 5}}}}}, tree)
+}
+
+func Test_projection_api_cached(t *testing.T) {
+	should := require.New(t)
+	api := jsoniter.Config{}.Froze()
+	projected := jsoniter.WithProjection(api, "owner.email", "id")
+	should.True(projected == jsoniter.WithProjection(api, " id, owner.email", "id"))
+	should.False(projected == jsoniter.WithProjection(api, "id"))
+	// the projections least recently used are dropped
+	for i := 0; i < 100; i++ {
+		jsoniter.WithProjection(api, "f"+strconv.Itoa(i))
+	}
+	should.False(projected == jsoniter.WithProjection(api, "id", "owner.email"))
+}
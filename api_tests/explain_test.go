@@ -0,0 +1,150 @@
+package test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unsafe"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+type explainedBase struct {
+	ID   int `json:"id,string"`
+	Name string
+}
+
+type explainedItem struct {
+	Label string `json:"label"`
+}
+
+type explainedStruct struct {
+	explainedBase
+	Name     string          `json:"name,alias=full_name"`
+	Nick     string          `json:"nick,omitempty"`
+	Revision int             `json:"revision,readonly"`
+	Secret   string          `json:"secret"`
+	Renamed  string          `json:"renamed"`
+	Items    []explainedItem `json:"items"`
+	Custom   string          `json:"custom"`
+	private  int
+}
+
+type explainExtension struct {
+	jsoniter.DummyExtension
+}
+
+func (extension *explainExtension) UpdateStructDescriptor(structDescriptor *jsoniter.StructDescriptor) {
+	if structDescriptor.Type.String() != "test.explainedStruct" {
+		return
+	}
+	fields := structDescriptor.Fields[:0]
+	for _, binding := range structDescriptor.Fields {
+		switch binding.Field.Name() {
+		case "Secret":
+			continue
+		case "Renamed":
+			binding.FromNames = []string{"RENAMED"}
+			binding.ToNames = []string{"RENAMED"}
+		}
+		fields = append(fields, binding)
+	}
+	structDescriptor.Fields = fields
+}
+
+type explainDecoder struct{}
+
+func (decoder *explainDecoder) Decode(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	iter.Skip()
+}
+
+func Test_explain(t *testing.T) {
+	should := require.New(t)
+	api := jsoniter.Config{}.Froze()
+	api.RegisterExtension(&explainExtension{})
+	jsoniter.RegisterFieldDecoderOf(api, reflect.TypeOf(explainedStruct{}), "Custom",
+		&explainDecoder{})
+	explanation := jsoniter.Explain(api, reflect.TypeOf(&explainedStruct{}))
+	should.Equal("*test.explainedStruct", explanation.Type)
+	should.Equal("*jsoniter.OptionalDecoder", explanation.Decoder)
+	elem := explanation.Elem
+	should.NotNil(elem)
+	should.Equal("*jsoniter.structDecoder", elem.Decoder)
+	should.Equal("*jsoniter.structEncoder", elem.Encoder)
+	should.Equal([]string{"Secret by *test.explainExtension"}, elem.Removed)
+
+	fields := map[string]*jsoniter.FieldExplanation{}
+	for _, field := range elem.Fields {
+		fields[field.Field] = field
+	}
+	should.Len(elem.Fields, 9)
+	should.True(fields["explainedBase.ID"].StringMode)
+	should.Equal([]string{"id"}, fields["explainedBase.ID"].FromNames)
+	should.Equal([]string{"Name"}, fields["explainedBase.Name"].ToNames)
+	should.Equal([]string{"name", "full_name"}, fields["Name"].FromNames)
+	should.Empty(fields["Name"].Conflicts)
+	should.True(fields["Nick"].OmitEmpty)
+	should.Empty(fields["Revision"].FromNames)
+	should.Equal([]string{"revision"}, fields["Revision"].ToNames)
+	should.Equal([]string{"RENAMED"}, fields["Renamed"].ToNames)
+	should.Equal([]string{"*test.explainExtension"}, fields["Renamed"].UpdatedBy)
+	should.Empty(fields["Nick"].UpdatedBy)
+	should.Equal("RegisterFieldDecoderOf", fields["Custom"].DecoderSource)
+	should.Equal("", fields["Custom"].EncoderSource)
+	should.Empty(fields["private"].FromNames)
+	should.NotNil(fields["Items"].Struct)
+	should.Equal("label", fields["Items"].Struct.Fields[0].ToNames[0])
+
+	dump := explanation.String()
+	should.Contains(dump, "field Name string\n")
+	should.Contains(dump, `from "name" "full_name", to "name"`)
+	should.Contains(dump, "updated by *test.explainExtension")
+	should.Contains(dump, "removed Secret by *test.explainExtension")
+	should.Contains(dump, "decoder *test.explainDecoder from RegisterFieldDecoderOf")
+	should.True(strings.HasPrefix(dump, "*test.explainedStruct\n"))
+}
+
+type explainedFirst struct {
+	Same string
+}
+
+type explainedSecond struct {
+	Same string
+}
+
+type explainedConflict struct {
+	explainedFirst
+	explainedSecond
+}
+
+func Test_explain_conflicts(t *testing.T) {
+	should := require.New(t)
+	explanation := jsoniter.Explain(jsoniter.ConfigDefault, reflect.TypeOf(explainedConflict{}))
+	should.Equal("*jsoniter.skipObjectDecoder", explanation.Decoder)
+	should.Equal("*jsoniter.structEncoder", explanation.Encoder)
+	should.Equal("explainedFirst.Same", explanation.Fields[0].Field)
+	should.Equal([]string{"Same"}, explanation.Fields[0].Conflicts)
+	should.Equal([]string{"Same"}, explanation.Fields[1].Conflicts)
+}
+
+func Test_explain_supplied_codecs(t *testing.T) {
+	should := require.New(t)
+	api := jsoniter.Config{}.Froze()
+	jsoniter.RegisterDecoderOf(api, reflect.TypeOf(explainedItem{}),
+		&explainDecoder{})
+	explanation := jsoniter.Explain(api, reflect.TypeOf(explainedItem{}))
+	should.Equal("jsoniter.DecoderExtension", explanation.DecoderSource)
+	should.Equal("", explanation.EncoderSource)
+	should.NotNil(explanation.Fields)
+}
+
+func Test_explain_recursive_container(t *testing.T) {
+	should := require.New(t)
+	for _, api := range []jsoniter.API{jsoniter.ConfigDefault, jsoniter.WithProjection(jsoniter.ConfigDefault, "r.x")} {
+		explanation := jsoniter.Explain(api, reflect.TypeOf(projectedTree{}))
+		should.Len(explanation.Fields, 4)
+		should.Equal("r", explanation.Fields[3].FromNames[0])
+		should.Nil(explanation.Fields[3].Struct)
+	}
+}
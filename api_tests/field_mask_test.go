@@ -0,0 +1,56 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+type fieldMaskOwner struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type fieldMaskItem struct {
+	ID     int                    `json:"id"`
+	Name   string                 `json:"name"`
+	Owner  *fieldMaskOwner        `json:"owner"`
+	Owners []fieldMaskOwner       `json:"owners"`
+	Extra  map[string]interface{} `json:"extra"`
+}
+
+func Test_field_mask(t *testing.T) {
+	item := fieldMaskItem{
+		ID:     1,
+		Name:   "item",
+		Owner:  &fieldMaskOwner{"a", "a@b"},
+		Owners: []fieldMaskOwner{{"b", "b@c"}},
+		Extra:  map[string]interface{}{"x": 1, "y": map[string]int{"z": 2, "w": 3}},
+	}
+	testCases := []struct {
+		mask   *jsoniter.FieldMask
+		output string
+	}{
+		{jsoniter.IncludeFields("id", "name", "owner.email"), `{"id":1,"name":"item","owner":{"email":"a@b"}}`},
+		{jsoniter.IncludeFields("owners.name", "owners", "extra.y.z"), `{"owners":[{"name":"b","email":"b@c"}],"extra":{"y":{"z":2}}}`},
+		{jsoniter.ExcludeFields("owner", "owners.email", "extra.x", "extra.y.w"), `{"id":1,"name":"item","owners":[{"name":"b"}],"extra":{"y":{"z":2}}}`},
+		{nil, `{"id":1,"name":"item","owner":{"name":"a","email":"a@b"},"owners":[{"name":"b","email":"b@c"}],"extra":{"x":1,"y":{"w":3,"z":2}}}`},
+	}
+	api := jsoniter.Config{SortMapKeys: true}.Froze()
+	for _, testCase := range testCases {
+		should := require.New(t)
+		stream := api.BorrowStream(nil)
+		stream.SetFieldMask(testCase.mask)
+		stream.WriteVal(item)
+		should.NoError(stream.Error)
+		should.Equal(testCase.output, string(stream.Buffer()))
+		api.ReturnStream(stream)
+	}
+	should := require.New(t)
+	stream := jsoniter.ConfigDefault.BorrowStream(nil)
+	defer jsoniter.ConfigDefault.ReturnStream(stream)
+	stream.SetFieldMask(jsoniter.ExcludeFields("x", "y.w"))
+	stream.WriteVal(item.Extra)
+	should.Equal(`{"y":{"z":2}}`, string(stream.Buffer()))
+}
@@ -34,3 +34,50 @@ func Test_marshal_indent_map(t *testing.T) {
 	should.Nil(err)
 	should.Equal("{\n  \"1\": 2\n}", string(output))
 }
+
+type rawOutputMarshaler string
+
+func (marshaler rawOutputMarshaler) MarshalJSON() ([]byte, error) {
+	return []byte(marshaler), nil
+}
+
+func Test_marshal_indent_marshaler_output(t *testing.T) {
+	should := require.New(t)
+	obj := struct {
+		Marshaler rawOutputMarshaler
+		Raw       json.RawMessage
+		Empty     json.RawMessage
+	}{rawOutputMarshaler(`{ "a" : [1, "x , y"],"b":{}}` + "\n"), json.RawMessage(`[ {"c": "\"]"} ]`), json.RawMessage(`[ ]`)}
+	expected, err := json.MarshalIndent(obj, "", "  ")
+	should.Nil(err)
+	output, err := jsoniter.ConfigCompatibleWithStandardLibrary.MarshalIndent(obj, "", "  ")
+	should.Nil(err)
+	should.Equal(string(expected), string(output))
+	expected, err = json.Marshal(obj)
+	should.Nil(err)
+	output, err = jsoniter.Marshal(obj)
+	should.Nil(err)
+	should.Equal(string(expected), string(output))
+}
+
+func Test_marshal_indent_unbalanced_marshaler_output(t *testing.T) {
+	should := require.New(t)
+	obj := struct {
+		X rawOutputMarshaler
+		Y int
+	}{rawOutputMarshaler(`{"a":[1`), 2}
+	output, err := jsoniter.MarshalIndent(obj, "", "  ")
+	should.Nil(err)
+	should.Equal("{\n  \"X\": {\"a\":[1,\n  \"Y\": 2\n}", string(output))
+}
+
+func Test_marshal_invalid_marshaler_output(t *testing.T) {
+	should := require.New(t)
+	for _, invalid := range []string{``, `{"a":`, `[1,]`, `1 2`, `nul`} {
+		_, err := json.Marshal(rawOutputMarshaler(invalid))
+		should.Error(err, invalid)
+		_, err = jsoniter.ConfigCompatibleWithStandardLibrary.Marshal(rawOutputMarshaler(invalid))
+		should.Error(err, invalid)
+		should.Contains(err.Error(), "error calling MarshalJSON", invalid)
+	}
+}
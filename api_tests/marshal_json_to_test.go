@@ -0,0 +1,127 @@
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+type streamPoint struct {
+	X int
+	Y int
+}
+
+func (point streamPoint) MarshalJSONTo(stream *jsoniter.Stream) error {
+	stream.WriteArrayStart()
+	stream.WriteInt(point.X)
+	stream.WriteMore()
+	stream.WriteInt(point.Y)
+	stream.WriteArrayEnd()
+	return nil
+}
+
+func (point streamPoint) MarshalJSON() ([]byte, error) {
+	return []byte(`"marshaler"`), nil
+}
+
+func (point *streamPoint) UnmarshalJSONFrom(iter *jsoniter.Iterator) error {
+	var coords []int
+	iter.ReadVal(&coords)
+	if len(coords) != 2 {
+		return errors.New("point needs two coordinates")
+	}
+	point.X, point.Y = coords[0], coords[1]
+	return nil
+}
+
+func (point *streamPoint) UnmarshalJSON(data []byte) error {
+	return errors.New("UnmarshalJSON should not be called")
+}
+
+type streamFailure struct{}
+
+func (failure *streamFailure) MarshalJSONTo(stream *jsoniter.Stream) error {
+	return errors.New("cannot encode")
+}
+
+func Test_marshal_json_to(t *testing.T) {
+	should := require.New(t)
+	api := jsoniter.ConfigCompatibleWithStandardLibrary
+	type Shape struct {
+		Origin streamPoint
+		End    *streamPoint
+	}
+	output, err := api.MarshalToString(Shape{Origin: streamPoint{1, 2}, End: &streamPoint{3, 4}})
+	should.NoError(err)
+	should.Equal(`{"Origin":[1,2],"End":[3,4]}`, output)
+	output, err = api.MarshalToString(Shape{})
+	should.NoError(err)
+	should.Equal(`{"Origin":[0,0],"End":null}`, output)
+
+	var shape Shape
+	should.NoError(api.UnmarshalFromString(`{"Origin":[5,6],"End":[7,8]}`, &shape))
+	should.Equal(streamPoint{5, 6}, shape.Origin)
+	should.Equal(&streamPoint{7, 8}, shape.End)
+	err = api.UnmarshalFromString(`{"Origin":[5]}`, &shape)
+	should.Error(err)
+	should.Contains(err.Error(), "point needs two coordinates")
+
+	_, err = api.Marshal(struct{ Failure streamFailure }{})
+	should.Error(err)
+	should.Contains(err.Error(), "cannot encode")
+	_, err = json.Marshal(struct{ Failure streamFailure }{})
+	should.NoError(err)
+}
+
+type streamCounter struct {
+	N int
+}
+
+func (counter *streamCounter) MarshalJSONTo(stream *jsoniter.Stream) error {
+	stream.WriteInt(counter.N)
+	return nil
+}
+
+func (counter *streamCounter) UnmarshalJSONFrom(iter *jsoniter.Iterator) error {
+	counter.N = iter.ReadInt()
+	return nil
+}
+
+func Test_marshal_json_to_by_pointer_no_allocation(t *testing.T) {
+	should := require.New(t)
+	type Counted struct {
+		Counter streamCounter
+	}
+	type Plain struct {
+		Counter int
+	}
+	obj := Counted{streamCounter{1}}
+	plain := Plain{1}
+	stream := jsoniter.ConfigDefault.BorrowStream(nil)
+	defer jsoniter.ConfigDefault.ReturnStream(stream)
+	stream.WriteVal(&obj)
+	should.Equal(`{"Counter":1}`, string(stream.Buffer()))
+	stream.WriteVal(&plain)
+	allocs := testing.AllocsPerRun(100, func() {
+		stream.Reset(nil)
+		stream.WriteVal(&obj)
+	})
+	should.Equal(testing.AllocsPerRun(100, func() {
+		stream.Reset(nil)
+		stream.WriteVal(&plain)
+	}), allocs, "no more allocation than an int field")
+
+	input := []byte(`2`)
+	iter := jsoniter.ConfigDefault.BorrowIterator(input)
+	defer jsoniter.ConfigDefault.ReturnIterator(iter)
+	iter.ReadVal(&obj.Counter)
+	should.Equal(Counted{streamCounter{2}}, obj)
+	allocs = testing.AllocsPerRun(100, func() {
+		iter.ResetBytes(input)
+		iter.ReadVal(&obj.Counter)
+	})
+	should.Equal(float64(0), allocs)
+}
@@ -0,0 +1,78 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/json-iterator/go"
+	"github.com/modern-go/reflect2"
+	"github.com/stretchr/testify/require"
+)
+
+type precompileAddress struct {
+	City string
+}
+
+type precompilePerson struct {
+	Name    string
+	Home    *precompileAddress
+	Friends []precompilePerson
+	Labels  map[string]precompileAddress
+	Extra   interface{}
+	updates chan int
+	Hook    func() `json:"-"`
+}
+
+type precompileBroken struct {
+	Name     string
+	Updates  chan int
+	Callback func()
+	Index    map[precompileAddress]int
+	Nested   struct {
+		Updates chan string
+	}
+	Unknown string `json:"unknown,format:nonexistent"`
+}
+
+func Test_precompile(t *testing.T) {
+	should := require.New(t)
+	api := jsoniter.Config{}.Froze()
+	should.Nil(jsoniter.Precompile(api, precompilePerson{}))
+	should.NotNil(api.DecoderOf(reflect2.TypeOf(&precompileAddress{})))
+	output, err := api.MarshalToString(precompilePerson{Name: "a", Home: &precompileAddress{City: "b"}})
+	should.Nil(err)
+	should.Equal(`{"Name":"a","Home":{"City":"b"},"Friends":null,"Labels":null,"Extra":null}`, output)
+}
+
+func Test_precompile_errors(t *testing.T) {
+	should := require.New(t)
+	api := jsoniter.Config{}.Froze()
+	err := jsoniter.Precompile(api, &precompileBroken{}, precompilePerson{})
+	should.NotNil(err)
+	precompileErr, isPrecompileErr := err.(*jsoniter.PrecompileError)
+	should.True(isPrecompileErr)
+	should.Len(precompileErr.Errors, 5, err.Error())
+	should.Contains(err.Error(), "Updates")
+	should.Contains(err.Error(), "Callback")
+	should.Contains(err.Error(), "precompileAddress")
+	should.Contains(err.Error(), "chan string")
+	should.Contains(err.Error(), "unknown format nonexistent")
+	// the codecs stay lazy, the errors are reported again on use
+	_, err = api.Marshal(&precompileBroken{Updates: make(chan int)})
+	should.NotNil(err)
+}
+
+func Test_precompile_errors_of_fields_of_same_type(t *testing.T) {
+	should := require.New(t)
+	type twoChannels struct {
+		C chan int
+		D chan int
+	}
+	err := jsoniter.Precompile(jsoniter.Config{}.Froze(), twoChannels{})
+	should.NotNil(err)
+	should.Len(err.(*jsoniter.PrecompileError).Errors, 2, err.Error())
+	should.Contains(err.Error(), "C: chan int is unsupported type")
+	should.Contains(err.Error(), "D: chan int is unsupported type")
+	_, err = jsoniter.Marshal(twoChannels{})
+	should.NotNil(err)
+	should.Contains(err.Error(), "C: chan int is unsupported type")
+}
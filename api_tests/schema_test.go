@@ -0,0 +1,200 @@
+package test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+type schemaLevel int
+
+func (level schemaLevel) JSONSchema() jsoniter.JSONSchema {
+	return jsoniter.JSONSchema{"type": "integer", "enum": []int{1, 2, 3}}
+}
+
+type schemaBase struct {
+	ID int64 `json:"id,string"`
+}
+
+type schemaNode struct {
+	schemaBase
+	Name     string                    `json:"name,alias=full_name"`
+	Nick     string                    `json:"nick,omitempty"`
+	Age      uint8                     `json:"age"`
+	Score    float64                   `json:"score"`
+	Active   bool                      `json:"active"`
+	Data     []byte                    `json:"data"`
+	Created  time.Time                 `json:"created"`
+	Unix     time.Time                 `json:"unix,format:unix"`
+	Level    schemaLevel               `json:"level"`
+	Parent   *schemaNode               `json:"parent,omitempty"`
+	Children []schemaNode              `json:"children"`
+	Counts   map[int]string            `json:"counts"`
+	Labels   map[string]string         `json:"labels"`
+	Extra    interface{}               `json:"extra"`
+	Raw      json.RawMessage           `json:"raw"`
+	Password string                    `json:"password,writeonly"`
+	Revision int                       `json:"revision,readonly"`
+	Email    jsoniter.Nullable[string] `json:"email"`
+	private  string
+}
+
+func Test_schema(t *testing.T) {
+	should := require.New(t)
+	schema, err := jsoniter.Schema(jsoniter.ConfigDefault, reflect.TypeOf(schemaNode{}))
+	should.Nil(err)
+	should.Equal(jsoniter.JSONSchemaDialect, schema["$schema"])
+	should.Equal("#/$defs/test.schemaNode", schema["$ref"])
+	node := schema["$defs"].(jsoniter.JSONSchema)["test.schemaNode"].(jsoniter.JSONSchema)
+	should.Equal("object", node["type"])
+	should.Equal([]string{"id", "name", "age", "score", "active", "data", "created", "unix",
+		"level", "children", "counts", "labels", "extra", "raw", "revision"}, node["required"])
+	should.Nil(node["additionalProperties"])
+	properties := node["properties"].(jsoniter.JSONSchema)
+	should.Len(properties, 20)
+	expected := map[string]string{
+		"id":        `{"contentMediaType":"application/json","contentSchema":{"type":"integer"},"type":"string"}`,
+		"name":      `{"type":"string"}`,
+		"full_name": `{"type":"string"}`,
+		"age":       `{"maximum":255,"minimum":0,"type":"integer"}`,
+		"score":     `{"type":"number"}`,
+		"active":    `{"type":"boolean"}`,
+		"data":      `{"contentEncoding":"base64","type":["string","null"]}`,
+		"created":   `{"format":"date-time","type":"string"}`,
+		"unix":      `{"type":"integer"}`,
+		"level":     `{"enum":[1,2,3],"type":"integer"}`,
+		"parent":    `{"anyOf":[{"$ref":"#/$defs/test.schemaNode"},{"type":"null"}]}`,
+		"children":  `{"items":{"$ref":"#/$defs/test.schemaNode"},"type":["array","null"]}`,
+		"counts":    `{"additionalProperties":{"type":"string"},"propertyNames":{"pattern":"^-?[0-9]+$"},"type":["object","null"]}`,
+		"labels":    `{"additionalProperties":{"type":"string"},"type":["object","null"]}`,
+		"extra":     `{}`,
+		"raw":       `{}`,
+		"password":  `{"type":"string","writeOnly":true}`,
+		"revision":  `{"readOnly":true,"type":"integer"}`,
+		"email":     `{"type":["string","null"]}`,
+	}
+	for name, expectedSchema := range expected {
+		output, err := jsoniter.ConfigCompatibleWithStandardLibrary.MarshalToString(properties[name])
+		should.Nil(err)
+		should.Equal(expectedSchema, output, name)
+	}
+}
+
+type schemaRenameExtension struct {
+	jsoniter.DummyExtension
+}
+
+func (extension *schemaRenameExtension) UpdateStructDescriptor(structDescriptor *jsoniter.StructDescriptor) {
+	for _, binding := range structDescriptor.Fields {
+		binding.ToNames = []string{strings.ToUpper(binding.Field.Name())}
+		binding.FromNames = binding.ToNames
+	}
+}
+
+type schemaPoint struct {
+	X, Y int32
+}
+
+type schemaEncoder struct {
+	jsoniter.ValEncoder
+}
+
+func (encoder *schemaEncoder) JSONSchema() jsoniter.JSONSchema {
+	return jsoniter.JSONSchema{"type": "string", "pattern": "^[0-9]+,[0-9]+$"}
+}
+
+type schemaShape struct {
+	Origin schemaPoint `json:"origin"`
+	Events chan int    `json:"-"`
+}
+
+func Test_schema_of_config(t *testing.T) {
+	should := require.New(t)
+	api := jsoniter.Config{DisallowUnknownFields: true}.Froze()
+	api.RegisterExtension(&schemaRenameExtension{})
+	schema, err := jsoniter.Schema(api, reflect.TypeOf(schemaPoint{}))
+	should.Nil(err)
+	output, err := jsoniter.ConfigCompatibleWithStandardLibrary.MarshalToString(schema)
+	should.Nil(err)
+	should.Equal(`{"$defs":{"test.schemaPoint":{"additionalProperties":false,`+
+		`"properties":{"X":{"maximum":2147483647,"minimum":-2147483648,"type":"integer"},`+
+		`"Y":{"maximum":2147483647,"minimum":-2147483648,"type":"integer"}},"required":["X","Y"],"type":"object"}},`+
+		`"$ref":"#/$defs/test.schemaPoint","$schema":"https://json-schema.org/draft/2020-12/schema"}`, output)
+
+	api = jsoniter.Config{}.Froze()
+	jsoniter.RegisterEncoderOf(api, reflect.TypeOf(schemaPoint{}), &schemaEncoder{})
+	schema, err = jsoniter.Schema(api, reflect.TypeOf([]schemaShape{}))
+	should.Nil(err)
+	output, err = jsoniter.ConfigCompatibleWithStandardLibrary.MarshalToString(schema)
+	should.Nil(err)
+	should.Equal(`{"$defs":{"test.schemaShape":{"properties":{"origin":{"pattern":"^[0-9]+,[0-9]+$","type":"string"}},`+
+		`"required":["origin"],"type":"object"}},"$schema":"https://json-schema.org/draft/2020-12/schema",`+
+		`"items":{"$ref":"#/$defs/test.schemaShape"},"type":["array","null"]}`, output)
+}
+
+func Test_schema_of_unsupported_type(t *testing.T) {
+	should := require.New(t)
+	type withChan struct {
+		Events chan int
+	}
+	_, err := jsoniter.Schema(jsoniter.ConfigDefault, reflect.TypeOf(withChan{}))
+	should.NotNil(err)
+	should.Contains(err.Error(), "chan int")
+}
+
+type schemaProjected struct {
+	A int32            `json:"a"`
+	B schemaPoint      `json:"b"`
+	C *schemaProjected `json:"c,omitempty"`
+}
+
+func Test_schema_of_projection(t *testing.T) {
+	should := require.New(t)
+	api := jsoniter.WithProjection(jsoniter.Config{DisallowUnknownFields: true}.Froze(), "b.X", "c.a")
+	schema, err := jsoniter.Schema(api, reflect.TypeOf(schemaProjected{}))
+	should.Nil(err)
+	output, err := jsoniter.ConfigCompatibleWithStandardLibrary.MarshalToString(schema)
+	should.Nil(err)
+	var decoded struct {
+		AdditionalProperties *bool `json:"additionalProperties"`
+		Properties           map[string]struct {
+			ReadOnly   bool `json:"readOnly"`
+			Properties map[string]struct {
+				ReadOnly bool `json:"readOnly"`
+			} `json:"properties"`
+		} `json:"properties"`
+	}
+	should.Nil(jsoniter.UnmarshalFromString(output, &decoded))
+	// the fields left out of the projection are not decoded, unknown fields are skipped
+	should.Nil(decoded.AdditionalProperties)
+	should.True(decoded.Properties["a"].ReadOnly)
+	should.False(decoded.Properties["b"].ReadOnly)
+	should.False(decoded.Properties["b"].Properties["X"].ReadOnly)
+	should.True(decoded.Properties["b"].Properties["Y"].ReadOnly)
+	should.False(decoded.Properties["c"].Properties["a"].ReadOnly)
+	should.True(decoded.Properties["c"].Properties["b"].ReadOnly)
+
+	explanation := jsoniter.Explain(api, reflect.TypeOf(schemaProjected{}))
+	should.Equal([]string{"a"}, explanation.Fields[0].Conflicts)
+	should.Nil(explanation.Fields[1].Struct.Fields[0].Conflicts)
+	should.Equal([]string{"Y"}, explanation.Fields[1].Struct.Fields[1].Conflicts)
+}
+
+func Test_schema_of_projection_of_recursive_type(t *testing.T) {
+	should := require.New(t)
+	for _, projection := range []string{"r.x", "kids.x,kids.kids.x,y"} {
+		schema, err := jsoniter.Schema(jsoniter.WithProjection(jsoniter.ConfigDefault, projection), reflect.TypeOf(projectedTree{}))
+		should.Nil(err, projection)
+		data, err := jsoniter.Marshal(schema)
+		should.Nil(err)
+		validator, err := jsoniter.CompileSchema(data)
+		should.Nil(err)
+		should.Nil(validator.Validate([]byte(`{"x":1,"y":2,"kids":[{"x":3,"y":4,"kids":[],"r":null}],"r":[[],[[]]]}`)), projection)
+		should.NotNil(validator.Validate([]byte(`{"x":1,"y":2,"kids":[],"r":[[1]]}`)), projection)
+	}
+}
@@ -0,0 +1,172 @@
+package test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+const validatorSchema = `{
+	"type": "object",
+	"required": ["name", "tags"],
+	"properties": {
+		"name": {"type": "string", "minLength": 1, "pattern": "^[a-z]+$"},
+		"age": {"type": "integer", "minimum": 0, "exclusiveMaximum": 150},
+		"role": {"enum": ["admin", "user"]},
+		"tags": {"type": "array", "items": {"$ref": "#/$defs/tag"}, "maxItems": 2},
+		"point": {"type": "array", "prefixItems": [{"type": "number"}, {"type": "number"}], "items": false},
+		"contact": {"oneOf": [
+			{"type": "object", "required": ["email"]},
+			{"type": "object", "required": ["phone"]}
+		]},
+		"parent": {"anyOf": [{"type": "null"}, {"$ref": "#"}]}
+	},
+	"patternProperties": {"^x-": {"type": "string"}},
+	"additionalProperties": false,
+	"$defs": {"tag": {"type": "string", "maxLength": 3}}
+}`
+
+func Test_schema_validator(t *testing.T) {
+	should := require.New(t)
+	validator, err := jsoniter.CompileSchema([]byte(validatorSchema))
+	should.NoError(err)
+	valid := []string{
+		`{"name":"bob","tags":[]}`,
+		`{"name":"bob","age":1.0,"role":"user","tags":["a","b"],"point":[1,2],"x-id":"7"}`,
+		`{"name":"bob","tags":[],"contact":{"email":"b@b"},"parent":{"name":"ann","tags":["c"],"parent":null}}`,
+	}
+	for _, input := range valid {
+		should.NoError(validator.Validate([]byte(input)), input)
+		should.NoError(validator.ValidateReader(strings.NewReader(input)), input)
+	}
+	invalid := map[string]string{
+		`{"tags":[]}`:                                 `#: missing required property "name"`,
+		`{"name":1,"tags":[]}`:                        `#/name: expect string, but found number`,
+		`{"name":"","tags":[]}`:                       `#/name: expect at least 1 characters, but found 0`,
+		`{"name":"Bob","tags":[]}`:                    `#/name: "Bob" does not match ^[a-z]+$`,
+		`{"name":"bob","age":1.5,"tags":[]}`:          `#/age: expect integer, but found 1.5`,
+		`{"name":"bob","age":150,"tags":[]}`:          `#/age: 150 is not less than exclusiveMaximum 150`,
+		`{"name":"bob","role":"root","tags":[]}`:      `#/role: "root" is not one of the values allowed`,
+		`{"name":"bob","tags":["abcd"]}`:              `#/tags/0: expect at most 3 characters, but found 4`,
+		`{"name":"bob","tags":["a","b","c"]}`:         `#/tags: expect at most 2 items, but found 3`,
+		`{"name":"bob","tags":[],"point":[1,2,3]}`:    `#/point/2: no value is allowed`,
+		`{"name":"bob","tags":[],"x-id":7}`:           `#/x-id: expect string, but found number`,
+		`{"name":"bob","tags":[],"other":1}`:          `#/other: no value is allowed`,
+		`{"name":"bob","tags":[],"contact":{}}`:       `#/contact: valid against 0 schemas of oneOf, expect exactly 1`,
+		`{"name":"bob","tags":[],"parent":{"age":1}}`: `#/parent: not valid against any schema of anyOf`,
+	}
+	for input, msg := range invalid {
+		err := validator.Validate([]byte(input))
+		should.Error(err, input)
+		should.Contains(err.Error(), "validate: "+msg, input)
+		err = validator.ValidateReader(strings.NewReader(input))
+		should.Error(err, input)
+		should.Contains(err.Error(), "validate: "+msg, input)
+	}
+	should.Error(validator.Validate([]byte(`{"name":"bob","tags":[]}{}`)))
+	should.Error(validator.Validate([]byte(`{"name":"bob","tags":[`)))
+}
+
+func Test_schema_validator_before_decoding(t *testing.T) {
+	should := require.New(t)
+	validator, err := jsoniter.CompileSchema([]byte(validatorSchema))
+	should.NoError(err)
+	type person struct {
+		Name string   `json:"name"`
+		Age  int      `json:"age"`
+		Tags []string `json:"tags"`
+	}
+	// a small buffer, the documents span several reads
+	input := `{"name":"bob","tags":["a"]} {"name":"ann","age":7,"tags":["b","c"],"parent":{"name":"bob","tags":[]}} {"name":"x"}`
+	iter := jsoniter.Parse(jsoniter.ConfigDefault, strings.NewReader(input), 8)
+	validator.ValidateNext(iter)
+	should.NoError(iter.Error)
+	validator.ValidateNext(iter)
+	should.NoError(iter.Error)
+	validator.ValidateNext(iter)
+	should.Error(iter.Error)
+	should.Contains(iter.Error.Error(), `validate: #: missing required property "tags"`)
+
+	data := []byte(`{"name":"bob","age":-3,"tags":["a"]}`)
+	err = validator.Validate(data)
+	should.Error(err)
+	should.Contains(err.Error(), "validate: #/age: -3 is less than minimum 0")
+	var val person
+	should.NoError(jsoniter.Unmarshal(data, &val), "decoding does not validate")
+	should.Equal(person{Name: "bob", Age: -3, Tags: []string{"a"}}, val)
+}
+
+func Test_schema_validator_of_generated_schema(t *testing.T) {
+	should := require.New(t)
+	type item struct {
+		ID    int      `json:"id"`
+		Name  string   `json:"name"`
+		Price float64  `json:"price,omitempty"`
+		Items []*item  `json:"items,omitempty"`
+		Level uint8    `json:"level"`
+		Tags  []string `json:"tags"`
+	}
+	api := jsoniter.Config{DisallowUnknownFields: true}.Froze()
+	schema, err := jsoniter.Schema(api, reflect.TypeOf(item{}))
+	should.NoError(err)
+	data, err := jsoniter.Marshal(schema)
+	should.NoError(err)
+	validator, err := jsoniter.CompileSchema(data)
+	should.NoError(err)
+	encoded, err := api.Marshal(item{ID: 1, Name: "a", Items: []*item{{ID: 2, Level: 3}}})
+	should.NoError(err)
+	should.NoError(validator.Validate(encoded))
+	should.Error(validator.Validate([]byte(`{"id":1,"name":"a","level":256,"tags":null}`)))
+	should.Error(validator.Validate([]byte(`{"id":1,"name":"a","level":1,"tags":null,"unknown":1}`)))
+	should.Error(validator.Validate([]byte(`{"id":1,"name":"a","level":1}`)))
+}
+
+func Test_compile_schema_errors(t *testing.T) {
+	should := require.New(t)
+	for _, schema := range []string{
+		`[]`,
+		`{"type":"text"}`,
+		`{"$ref":"other.json"}`,
+		`{"$ref":"#/$defs/missing"}`,
+		`{"pattern":"("}`,
+		`{"uniqueItems":true}`,
+		`{"items":{"contains":{}}}`,
+		`{`,
+	} {
+		_, err := jsoniter.CompileSchema([]byte(schema))
+		should.Error(err, schema)
+	}
+	_, err := jsoniter.CompileSchema([]byte(`{"uniqueItems":false,"format":"email","$defs":{"a":{"$ref":"#/$defs/a"}}}`))
+	should.NoError(err)
+}
+
+func Test_schema_validator_failure_of_large_value(t *testing.T) {
+	should := require.New(t)
+	validator, err := jsoniter.CompileSchema([]byte(`{"enum":["a",1]}`))
+	should.NoError(err)
+	err = validator.Validate([]byte(`"` + strings.Repeat("x", 1000) + `"`))
+	should.Error(err)
+	should.Contains(err.Error(), `validate: #: "`+strings.Repeat("x", 40)+`"... is not one of the values allowed`)
+	err = validator.Validate([]byte(`{"a":"` + strings.Repeat("x", 1000) + `"}`))
+	should.Error(err)
+	should.Contains(err.Error(), "validate: #: object is not one of the values allowed")
+	should.NotContains(strings.Split(err.Error(), ", error found")[0], "xxx")
+}
+
+func Test_schema_validator_of_deep_value(t *testing.T) {
+	should := require.New(t)
+	validator, err := jsoniter.CompileSchema([]byte(validatorSchema))
+	should.NoError(err)
+	depth := 5000
+	input := strings.Repeat(`{"name":"a","tags":[],"parent":`, depth) + "null" + strings.Repeat("}", depth)
+	should.NoError(validator.Validate([]byte(input)))
+	input = strings.Repeat(`{"name":"a","tags":[],"parent":`, depth) + `{"name":"a"}` + strings.Repeat("}", depth)
+	err = validator.Validate([]byte(input))
+	should.Error(err)
+	should.Contains(err.Error(), "validate: #/parent: not valid against any schema of anyOf: ")
+	should.Contains(err.Error(), "#/parent/parent/parent: not valid against any schema of anyOf")
+	should.Less(len(err.Error()), 1024, "the failures of the nested anyOf are cut")
+}
@@ -0,0 +1,104 @@
+package test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+type typedPoint struct {
+	X     int    `json:"x"`
+	Y     int    `json:"y"`
+	Label string `json:"label,omitempty"`
+}
+
+func Test_unmarshal_as_and_marshal_t(t *testing.T) {
+	should := require.New(t)
+	point, err := jsoniter.UnmarshalAs[typedPoint]([]byte(`{"x":1,"y":2}`))
+	should.NoError(err)
+	should.Equal(typedPoint{X: 1, Y: 2}, point)
+	_, err = jsoniter.UnmarshalAs[typedPoint]([]byte(`{"x":1} {}`))
+	should.Error(err)
+	output, err := jsoniter.MarshalT(point)
+	should.NoError(err)
+	should.Equal(`{"x":1,"y":2}`, string(output))
+	numbers, err := jsoniter.UnmarshalAs[map[string][]int]([]byte(`{"a":[1,2]}`))
+	should.NoError(err)
+	should.Equal(map[string][]int{"a": {1, 2}}, numbers)
+	output, err = jsoniter.MarshalT[interface{}](nil)
+	should.NoError(err)
+	should.Equal(`null`, string(output))
+}
+
+func Test_typed_codecs(t *testing.T) {
+	should := require.New(t)
+	api := jsoniter.Config{SortMapKeys: true, DisallowUnknownFields: true}.Froze()
+	decoder := jsoniter.DecoderFor[typedPoint](api)
+	encoder := jsoniter.EncoderFor[typedPoint](api)
+	var point typedPoint
+	should.NoError(decoder.Unmarshal([]byte(`{"x":3,"label":"p"}`), &point))
+	should.Equal(typedPoint{X: 3, Label: "p"}, point)
+	should.Error(decoder.Unmarshal([]byte(`{"z":3}`), &point))
+	output, err := encoder.Marshal(&point)
+	should.NoError(err)
+	should.Equal(`{"x":3,"y":0,"label":"p"}`, string(output))
+	output, err = encoder.Marshal(nil)
+	should.NoError(err)
+	should.Equal(`null`, string(output))
+
+	// an iterator of another config decodes with its own codecs
+	iter := jsoniter.ConfigDefault.BorrowIterator([]byte(`{"z":3,"y":4}`))
+	defer jsoniter.ConfigDefault.ReturnIterator(iter)
+	decoder.Decode(iter, &point)
+	should.NoError(iter.Error)
+	should.Equal(4, point.Y)
+}
+
+func Test_typed_stream_codecs(t *testing.T) {
+	should := require.New(t)
+	decoder := jsoniter.DecoderFor[typedPoint](jsoniter.ConfigDefault)
+	stream := jsoniter.NewDecoder(strings.NewReader(`{"x":1} {"x":2}`))
+	points := []typedPoint{}
+	for {
+		var point typedPoint
+		err := decoder.DecodeFrom(stream, &point)
+		if err == io.EOF {
+			break
+		}
+		should.NoError(err)
+		points = append(points, point)
+	}
+	should.Equal([]typedPoint{{X: 1}, {X: 2}}, points)
+
+	var buf bytes.Buffer
+	encoder := jsoniter.EncoderFor[typedPoint](jsoniter.ConfigDefault)
+	adapter := jsoniter.NewEncoder(&buf)
+	for i := range points {
+		should.NoError(encoder.EncodeTo(adapter, &points[i]))
+	}
+	adapter.SetIndent("", "  ")
+	should.NoError(encoder.EncodeTo(adapter, &points[0]))
+	should.Equal("{\"x\":1,\"y\":0}\n{\"x\":2,\"y\":0}\n{\n  \"x\": 1,\n  \"y\": 0\n}\n", buf.String())
+}
+
+func Test_typed_codecs_do_not_allocate(t *testing.T) {
+	should := require.New(t)
+	decoder := jsoniter.DecoderFor[typedPoint](jsoniter.ConfigDefault)
+	encoder := jsoniter.EncoderFor[typedPoint](jsoniter.ConfigDefault)
+	data := []byte(`{"x":1,"y":2}`)
+	iter := jsoniter.ConfigDefault.BorrowIterator(data)
+	stream := jsoniter.ConfigDefault.BorrowStream(nil)
+	var point typedPoint
+	allocs := testing.AllocsPerRun(100, func() {
+		iter.ResetBytes(data)
+		decoder.Decode(iter, &point)
+		stream.Reset(nil)
+		encoder.Encode(stream, &point)
+	})
+	should.Equal(float64(0), allocs)
+	should.Equal(`{"x":1,"y":2}`, string(stream.Buffer()))
+}
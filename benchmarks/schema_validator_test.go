@@ -0,0 +1,69 @@
+package test
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+type validatedNode struct {
+	Name  string         `json:"name"`
+	Tags  []string       `json:"tags,omitempty"`
+	Child *validatedNode `json:"child,omitempty"`
+}
+
+func compileNodeSchema(b *testing.B) *jsoniter.SchemaValidator {
+	schema, err := jsoniter.Schema(jsoniter.ConfigDefault, reflect.TypeOf(validatedNode{}))
+	if err != nil {
+		b.Fatal(err)
+	}
+	data, err := jsoniter.Marshal(schema)
+	if err != nil {
+		b.Fatal(err)
+	}
+	validator, err := jsoniter.CompileSchema(data)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return validator
+}
+
+// nested documents, every level validated against anyOf of the node schema and null
+func Benchmark_schema_validator_depth(b *testing.B) {
+	validator := compileNodeSchema(b)
+	for _, depth := range []int{100, 1000, 4000} {
+		data := []byte(strings.Repeat(`{"name":"n","child":`, depth) + "null" + strings.Repeat("}", depth))
+		b.Run(fmt.Sprintf("depth=%d", depth), func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			for i := 0; i < b.N; i++ {
+				if err := validator.Validate(data); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// flat documents of many nodes
+func Benchmark_schema_validator_size(b *testing.B) {
+	validator, err := jsoniter.CompileSchema([]byte(`{"type":"array","items":{"$ref":"#/$defs/node"},
+		"$defs":{"node":{"type":"object","required":["name"],"properties":{
+			"name":{"type":"string","minLength":1},"tags":{"type":"array","items":{"enum":["a","b"]}}}}}}`))
+	if err != nil {
+		b.Fatal(err)
+	}
+	for _, size := range []int{100, 10000} {
+		data := []byte("[" + strings.TrimSuffix(strings.Repeat(`{"name":"n","tags":["a","b"]},`, size), ",") + "]")
+		b.Run(fmt.Sprintf("size=%d", size), func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			for i := 0; i < b.N; i++ {
+				if err := validator.Validate(data); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
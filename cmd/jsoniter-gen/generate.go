@@ -0,0 +1,550 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const jsoniterPath = "github.com/json-iterator/go"
+
+const generatedHeader = "// Code generated by jsoniter-gen. DO NOT EDIT."
+
+// Generate returns the formatted source of the codecs of the named struct types of the package in dir
+func Generate(dir string, typeNames []string) ([]byte, error) {
+	pkg, err := loadPackage(dir)
+	if err != nil {
+		return nil, err
+	}
+	g := &generator{pkg: pkg, imports: map[string]string{}}
+	for _, typeName := range typeNames {
+		if err := g.generateType(typeName); err != nil {
+			return nil, err
+		}
+	}
+	return g.source()
+}
+
+// loadPackage type checks the package in dir, ignoring the files generated before.
+// Errors are tolerated, the types failing to check are encoded by reflection.
+func loadPackage(dir string) (*types.Package, error) {
+	buildPkg, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	files := []*ast.File{}
+	for _, name := range buildPkg.GoFiles {
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		if len(file.Comments) > 0 && strings.HasPrefix(file.Comments[0].Text(), generatedHeader[3:]) {
+			continue
+		}
+		files = append(files, file)
+	}
+	config := types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		Error:    func(err error) {},
+	}
+	pkg, _ := config.Check(buildPkg.ImportPath, fset, files, nil)
+	return pkg, nil
+}
+
+type generator struct {
+	pkg     *types.Package
+	imports map[string]string // path to name of the packages the generated code refers to
+	body    bytes.Buffer
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.body, format, args...)
+}
+
+func (g *generator) source() ([]byte, error) {
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "%s\n\npackage %s\n\nimport (\n", generatedHeader, g.pkg.Name())
+	paths := []string{}
+	for path := range g.imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		fmt.Fprintf(&src, "\t%s %q\n", g.imports[path], path)
+	}
+	src.WriteString(")\n")
+	src.Write(g.body.Bytes())
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %v\n%s", err, src.Bytes())
+	}
+	return formatted, nil
+}
+
+func (g *generator) qualifier(pkg *types.Package) string {
+	if pkg == g.pkg {
+		return ""
+	}
+	g.imports[pkg.Path()] = pkg.Name()
+	return pkg.Name()
+}
+
+func (g *generator) typeString(typ types.Type) string {
+	return types.TypeString(typ, g.qualifier)
+}
+
+// field is a struct field to encode or decode, possibly promoted from embedded structs
+type field struct {
+	expr      string // selector from the struct value
+	typ       types.Type
+	toNames   []string
+	fromNames []string
+	omitEmpty bool
+	quoted    bool
+	readOnly  bool
+	tagged    bool
+	levels    []int
+}
+
+func (g *generator) generateType(typeName string) error {
+	obj := g.pkg.Scope().Lookup(typeName)
+	if obj == nil {
+		return fmt.Errorf("type %s not found in package %s", typeName, g.pkg.Name())
+	}
+	named, isNamed := obj.Type().(*types.Named)
+	if !isNamed || named.TypeParams().Len() > 0 {
+		return fmt.Errorf("%s is not a non-generic named type", typeName)
+	}
+	structType, isStruct := named.Underlying().(*types.Struct)
+	if !isStruct {
+		return fmt.Errorf("%s is not a struct type", typeName)
+	}
+	g.imports[jsoniterPath] = "jsoniter"
+	fields, err := g.collectFields(typeName, structType, "obj", nil)
+	if err != nil {
+		return err
+	}
+	g.generateEncoder(typeName, fields)
+	g.generateDecoder(typeName, fields)
+	return nil
+}
+
+// collectFields lists the fields of the struct as describeStruct does
+func (g *generator) collectFields(typeName string, structType *types.Struct, expr string, levels []int) ([]*field, error) {
+	fields := []*field{}
+	for i := 0; i < structType.NumFields(); i++ {
+		structField := structType.Field(i)
+		tags := reflect.StructTag(structType.Tag(i))
+		tag, hasTag := tags.Lookup("json")
+		if tag == "-" || structField.Name() == "_" {
+			continue
+		}
+		fieldLevels := append(levels[:len(levels):len(levels)], i)
+		if isJsoniterType(structField.Type(), "FieldSet") {
+			return nil, fmt.Errorf("%s.%s: FieldSet is only recorded by reflection", typeName, structField.Name())
+		}
+		if _, hasGroups := tags.Lookup("groups"); hasGroups {
+			return nil, fmt.Errorf("%s.%s: groups are only supported by reflection", typeName, structField.Name())
+		}
+		tagParts := strings.Split(tag, ",")
+		if structField.Anonymous() && tagParts[0] == "" {
+			switch embedded := structField.Type().Underlying().(type) {
+			case *types.Struct:
+				promoted, err := g.collectFields(typeName, embedded, expr+"."+structField.Name(), fieldLevels)
+				if err != nil {
+					return nil, err
+				}
+				fields = append(fields, promoted...)
+				continue
+			case *types.Pointer:
+				if _, isStruct := embedded.Elem().Underlying().(*types.Struct); isStruct {
+					return nil, fmt.Errorf("%s.%s: embedded struct pointers are only supported by reflection", typeName, structField.Name())
+				}
+			}
+		}
+		f := &field{
+			expr:   expr + "." + structField.Name(),
+			typ:    structField.Type(),
+			tagged: hasTag && tag != "",
+			levels: fieldLevels,
+		}
+		names := []string{structField.Name()}
+		if tagParts[0] != "" {
+			names = []string{tagParts[0]}
+		}
+		if unicode.IsLower(rune(structField.Name()[0])) || structField.Name()[0] == '_' {
+			names = []string{}
+		}
+		f.omitEmpty = isJsoniterType(f.typ, "Nullable") || isJsoniterType(f.typ, "Optional")
+		f.toNames, f.fromNames = names, names
+		for _, tagPart := range tagParts[1:] {
+			switch {
+			case tagPart == "omitempty":
+				f.omitEmpty = true
+			case tagPart == "string":
+				f.quoted = true
+			case tagPart == "readonly":
+				f.readOnly = true
+			case tagPart == "writeonly":
+				f.toNames = []string{}
+			case strings.HasPrefix(tagPart, "alias="):
+				if len(names) > 0 {
+					f.fromNames = append(f.fromNames[:len(f.fromNames):len(f.fromNames)], tagPart[len("alias="):])
+				}
+			case tagPart == "redact" || strings.HasPrefix(tagPart, "format:") || strings.HasPrefix(tagPart, "precision="):
+				return nil, fmt.Errorf("%s.%s: tag option %s is only supported by reflection", typeName, structField.Name(), tagPart)
+			}
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// resolveConflict picks the fields named alike to keep, as resolveConflictBinding does
+func resolveConflict(old, new *field) (ignoreOld, ignoreNew bool) {
+	if new.tagged != old.tagged {
+		return new.tagged, old.tagged
+	}
+	if len(old.levels) > len(new.levels) {
+		return true, false
+	} else if len(new.levels) > len(old.levels) {
+		return false, true
+	}
+	return true, true
+}
+
+type namedField struct {
+	name  string
+	field *field
+}
+
+// encodedFields lists the fields to write in order, as encoderOfStruct does
+func encodedFields(fields []*field) []namedField {
+	type candidate struct {
+		namedField
+		ignored bool
+	}
+	candidates := []*candidate{}
+	for _, f := range fields {
+		for _, toName := range f.toNames {
+			new := &candidate{namedField: namedField{toName, f}}
+			for _, old := range candidates {
+				if old.name == toName {
+					old.ignored, new.ignored = resolveConflict(old.field, new.field)
+				}
+			}
+			candidates = append(candidates, new)
+		}
+	}
+	encoded := []namedField{}
+	for _, c := range candidates {
+		if !c.ignored {
+			encoded = append(encoded, c.namedField)
+		}
+	}
+	return encoded
+}
+
+// decodedFields lists the names read into each field, as decoderOfStruct does
+func decodedFields(fields []*field) []namedField {
+	byName := map[string]*field{}
+	order := []string{}
+	for _, f := range fields {
+		for _, fromName := range f.fromNames {
+			old, found := byName[fromName]
+			if !found {
+				order = append(order, fromName)
+			}
+			if old == nil {
+				byName[fromName] = f
+				continue
+			}
+			ignoreOld, ignoreNew := resolveConflict(old, f)
+			if ignoreOld {
+				byName[fromName] = nil
+			}
+			if !ignoreNew {
+				byName[fromName] = f
+			}
+		}
+	}
+	decoded := []namedField{}
+	for _, name := range order {
+		if byName[name] != nil {
+			decoded = append(decoded, namedField{name, byName[name]})
+		}
+	}
+	return decoded
+}
+
+func (g *generator) generateEncoder(typeName string, fields []*field) {
+	encoded := encodedFields(fields)
+	g.printf("\n// MarshalJSONTo writes obj into the stream, following the struct tags of %s\n", typeName)
+	g.printf("func (obj *%s) MarshalJSONTo(stream *jsoniter.Stream) error {\n", typeName)
+	if len(encoded) == 0 {
+		g.printf("stream.WriteEmptyObject()\nreturn nil\n}\n")
+		return
+	}
+	var body bytes.Buffer
+	// whether a field was written before is known until the first omitempty field
+	const (
+		noneWritten = iota
+		someWritten
+		maybeWritten
+	)
+	written, usesMore := noneWritten, false
+	for i, nf := range encoded {
+		f := nf.field
+		notEmpty := ""
+		if f.omitEmpty {
+			notEmpty = g.isNotEmpty(f.expr, f.typ)
+		}
+		if notEmpty != "" {
+			fmt.Fprintf(&body, "if %s {\n", notEmpty)
+		}
+		switch written {
+		case someWritten:
+			body.WriteString("stream.WriteMore()\n")
+		case maybeWritten:
+			body.WriteString("if more {\nstream.WriteMore()\n}\n")
+		}
+		fmt.Fprintf(&body, "stream.WriteObjectField(%q)\n", nf.name)
+		body.WriteString(g.encodeValue("stream", f.expr, f.typ, f.quoted))
+		if notEmpty == "" {
+			written = someWritten
+		} else {
+			if written != someWritten && i < len(encoded)-1 {
+				body.WriteString("more = true\n")
+				usesMore = true
+				written = maybeWritten
+			}
+			body.WriteString("}\n")
+		}
+	}
+	g.printf("stream.WriteObjectStart()\n")
+	if usesMore {
+		g.printf("more := false\n")
+	}
+	g.body.Write(body.Bytes())
+	g.printf("stream.WriteObjectEnd()\nreturn nil\n}\n")
+}
+
+func (g *generator) generateDecoder(typeName string, fields []*field) {
+	decoded := decodedFields(fields)
+	g.printf("\n// UnmarshalJSONFrom reads obj from the iterator, following the struct tags of %s\n", typeName)
+	g.printf("func (obj *%s) UnmarshalJSONFrom(iter *jsoniter.Iterator) error {\n", typeName)
+	g.printf("iter.ReadObjectRawCB(func(iter *jsoniter.Iterator, raw jsoniter.RawString) bool {\n")
+	g.printf("field, _ := raw.Bytes()\nif raw.ContainsEscapes() {\nfield = []byte(raw.String())\n}\n")
+	if len(decoded) == 0 {
+		g.printf("iter.SkipUnknownField(string(field))\nreturn true\n})\nreturn nil\n}\n")
+		return
+	}
+	// the names of a field share one case, exact names are matched first
+	cases := []*decodeCase{}
+	byField := map[*field]*decodeCase{}
+	for _, nf := range decoded {
+		c := byField[nf.field]
+		if c == nil {
+			c = &decodeCase{field: nf.field}
+			byField[nf.field] = c
+			cases = append(cases, c)
+		}
+		c.names = append(c.names, nf.name)
+	}
+	g.printf("switch string(field) {\n")
+	for _, c := range cases {
+		quotedNames := make([]string, len(c.names))
+		for i, name := range c.names {
+			quotedNames[i] = strconv.Quote(name)
+		}
+		g.printf("case %s:\n%s", strings.Join(quotedNames, ", "), g.decodeField(c.field))
+	}
+	// the name already in its folded form wins among the names folding alike, as in the reflective dispatch
+	sort.SliceStable(cases, func(i, j int) bool {
+		return cases[i].isFolded() && !cases[j].isFolded()
+	})
+	g.printf("default:\nswitch name := string(field); {\n")
+	for _, c := range cases {
+		matches := make([]string, len(c.names))
+		for i, name := range c.names {
+			matches[i] = fmt.Sprintf("iter.MatchField(name, %q)", name)
+		}
+		g.printf("case %s:\n%s", strings.Join(matches, ", "), g.decodeField(c.field))
+	}
+	g.printf("default:\niter.SkipUnknownField(name)\n}\n}\nreturn true\n})\nreturn nil\n}\n")
+}
+
+type decodeCase struct {
+	field *field
+	names []string
+}
+
+func (c *decodeCase) isFolded() bool {
+	return strings.ToLower(c.names[0]) == c.names[0]
+}
+
+func (g *generator) decodeField(f *field) string {
+	if f.readOnly {
+		return "iter.SkipReadOnlyField(string(field))\n"
+	}
+	return g.decodeValue("iter", f.expr, f.typ, f.quoted)
+}
+
+// isNotEmpty returns the condition to write an omitempty field, as the reflection codecs check it.
+// It is empty for the types never empty.
+func (g *generator) isNotEmpty(expr string, typ types.Type) string {
+	if isJsoniterType(typ, "Nullable") {
+		return expr + ".IsPresent()"
+	}
+	if isJsoniterType(typ, "Optional") {
+		return expr + ".IsSet()"
+	}
+	switch underlying := typ.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case underlying.Info()&types.IsBoolean != 0:
+			return expr
+		case underlying.Info()&types.IsString != 0:
+			return expr + ` != ""`
+		case underlying.Kind() == types.UnsafePointer:
+			return expr + " != nil"
+		default:
+			return expr + " != 0"
+		}
+	case *types.Slice, *types.Map:
+		return "len(" + expr + ") != 0"
+	case *types.Pointer, *types.Interface, *types.Chan, *types.Signature:
+		return expr + " != nil"
+	}
+	return ""
+}
+
+// basicWriters are the Stream methods writing the basic types directly
+var basicWriters = map[types.BasicKind]string{
+	types.Bool:    "WriteBool",
+	types.String:  "WriteStringValue",
+	types.Int:     "WriteInt",
+	types.Int8:    "WriteInt8",
+	types.Int16:   "WriteInt16",
+	types.Int32:   "WriteInt32",
+	types.Int64:   "WriteInt64",
+	types.Uint:    "WriteUint",
+	types.Uint8:   "WriteUint8",
+	types.Uint16:  "WriteUint16",
+	types.Uint32:  "WriteUint32",
+	types.Uint64:  "WriteUint64",
+	types.Float32: "WriteFloat32Value",
+	types.Float64: "WriteFloat64Value",
+}
+
+// basicReaders are the Iterator methods reading the basic types directly
+var basicReaders = map[types.BasicKind]string{
+	types.Bool:    "ReadBool",
+	types.String:  "ReadString",
+	types.Int:     "ReadInt",
+	types.Int8:    "ReadInt8",
+	types.Int16:   "ReadInt16",
+	types.Int32:   "ReadInt32",
+	types.Int64:   "ReadInt64",
+	types.Uint:    "ReadUint",
+	types.Uint8:   "ReadUint8",
+	types.Uint16:  "ReadUint16",
+	types.Uint32:  "ReadUint32",
+	types.Uint64:  "ReadUint64",
+	types.Float32: "ReadFloat32",
+	types.Float64: "ReadFloat64",
+}
+
+// directBasic returns the basic type read and written without a codec, nil for the types needing one
+func directBasic(typ types.Type) *types.Basic {
+	basic, isBasic := typ.Underlying().(*types.Basic)
+	if !isBasic || basicWriters[basic.Kind()] == "" || hasCodecMethods(typ) {
+		return nil
+	}
+	if named, isNamed := typ.(*types.Named); isNamed && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "encoding/json" {
+		// json.Number has its own codec
+		return nil
+	}
+	return basic
+}
+
+// hasCodecMethods reports whether the type or its pointer marshals itself
+func hasCodecMethods(typ types.Type) bool {
+	methods := types.NewMethodSet(types.NewPointer(typ))
+	for _, name := range []string{"MarshalJSONTo", "MarshalJSON", "MarshalText",
+		"UnmarshalJSONFrom", "UnmarshalJSON", "UnmarshalText"} {
+		if methods.Lookup(nil, name) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func isJsoniterType(typ types.Type, name string) bool {
+	named, isNamed := typ.(*types.Named)
+	if !isNamed || named.Obj().Pkg() == nil {
+		return false
+	}
+	return named.Obj().Pkg().Path() == jsoniterPath && named.Obj().Name() == name
+}
+
+func isStringKind(typ types.Type) bool {
+	basic, isBasic := typ.Underlying().(*types.Basic)
+	return isBasic && basic.Info()&types.IsString != 0
+}
+
+// encodeValue returns the statements writing expr into the stream
+func (g *generator) encodeValue(stream, expr string, typ types.Type, quoted bool) string {
+	if quoted && isStringKind(typ) {
+		return fmt.Sprintf("{\ninner := %[1]s.Pool().BorrowStream(nil)\n%[2]s%[1]s.WriteString(string(inner.Buffer()))\n%[1]s.Pool().ReturnStream(inner)\n}\n",
+			stream, g.encodeValue("inner", expr, typ, false))
+	}
+	if quoted {
+		return fmt.Sprintf("%[1]s.WriteRaw(`\"`)\n%[2]s%[1]s.WriteRaw(`\"`)\n", stream, g.encodeValue(stream, expr, typ, false))
+	}
+	basic := directBasic(typ)
+	if basic == nil {
+		return fmt.Sprintf("%s.WriteVal(&%s)\n", stream, expr)
+	}
+	value := expr
+	if !types.Identical(typ, types.Typ[basic.Kind()]) {
+		value = fmt.Sprintf("%s(%s)", types.Typ[basic.Kind()].Name(), expr)
+	}
+	return fmt.Sprintf("%s.%s(%s)\n", stream, basicWriters[basic.Kind()], value)
+}
+
+// decodeValue returns the statements reading expr from the iterator
+func (g *generator) decodeValue(iter, expr string, typ types.Type, quoted bool) string {
+	if quoted && isStringKind(typ) {
+		return fmt.Sprintf("{\ninner := %[1]s.Pool().BorrowIterator([]byte(%[1]s.ReadString()))\n%[2]s%[1]s.Pool().ReturnIterator(inner)\n}\n",
+			iter, g.decodeValue("inner", expr, typ, false))
+	}
+	if quoted {
+		return fmt.Sprintf("%s.ReadQuoted(func() {\n%s})\n", iter, g.decodeValue(iter, expr, typ, false))
+	}
+	basic := directBasic(typ)
+	if basic == nil {
+		return fmt.Sprintf("%s.ReadVal(&%s)\n", iter, expr)
+	}
+	value := fmt.Sprintf("%s.%s()", iter, basicReaders[basic.Kind()])
+	if !types.Identical(typ, types.Typ[basic.Kind()]) {
+		value = fmt.Sprintf("%s(%s)", g.typeString(typ), value)
+	}
+	if basic.Kind() == types.String {
+		return fmt.Sprintf("%s = %s\n", expr, value)
+	}
+	return fmt.Sprintf("if !%s.ReadNil() {\n%s = %s\n}\n", iter, expr, value)
+}
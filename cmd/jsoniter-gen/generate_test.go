@@ -0,0 +1,36 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_generated_sample_is_up_to_date(t *testing.T) {
+	should := require.New(t)
+	dir := filepath.Join("internal", "sample")
+	src, err := Generate(dir, []string{"Person", "Address", "Empty"})
+	should.NoError(err)
+	committed, err := ioutil.ReadFile(filepath.Join(dir, "person_jsoniter.go"))
+	should.NoError(err)
+	should.Equal(string(committed), string(src), "run go generate in internal/sample")
+}
+
+func Test_unsupported_types(t *testing.T) {
+	should := require.New(t)
+	dir := filepath.Join("testdata", "unsupported")
+	for typeName, message := range map[string]string{
+		"Missing":         "type Missing not found",
+		"NotStruct":       "NotStruct is not a struct type",
+		"Redacted":        "Redacted.Secret: tag option redact",
+		"Formatted":       "Formatted.Created: tag option format:unix",
+		"Grouped":         "Grouped.Internal: groups",
+		"EmbeddedPointer": "EmbeddedPointer.Base: embedded struct pointers",
+	} {
+		_, err := Generate(dir, []string{typeName})
+		should.Error(err, typeName)
+		should.Contains(err.Error(), message)
+	}
+}
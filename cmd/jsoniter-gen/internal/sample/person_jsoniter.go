@@ -0,0 +1,363 @@
+// Code generated by jsoniter-gen. DO NOT EDIT.
+
+package sample
+
+import (
+	jsoniter "github.com/json-iterator/go"
+)
+
+// MarshalJSONTo writes obj into the stream, following the struct tags of Person
+func (obj *Person) MarshalJSONTo(stream *jsoniter.Stream) error {
+	stream.WriteObjectStart()
+	stream.WriteObjectField("created")
+	stream.WriteVal(&obj.Audit.Created)
+	stream.WriteMore()
+	stream.WriteObjectField("version")
+	stream.WriteInt(obj.Audit.Version)
+	stream.WriteMore()
+	stream.WriteObjectField("Note")
+	stream.WriteStringValue(obj.Audit.Note)
+	stream.WriteMore()
+	stream.WriteObjectField("name")
+	stream.WriteStringValue(obj.Name)
+	if obj.Nick != "" {
+		stream.WriteMore()
+		stream.WriteObjectField("nick")
+		stream.WriteStringValue(obj.Nick)
+	}
+	if obj.Age != 0 {
+		stream.WriteMore()
+		stream.WriteObjectField("age")
+		stream.WriteInt(obj.Age)
+	}
+	stream.WriteMore()
+	stream.WriteObjectField("height")
+	stream.WriteFloat32Value(obj.Height)
+	if obj.Score != 0 {
+		stream.WriteMore()
+		stream.WriteObjectField("score")
+		stream.WriteFloat64Value(float64(obj.Score))
+	}
+	stream.WriteMore()
+	stream.WriteObjectField("active")
+	stream.WriteRaw(`"`)
+	stream.WriteBool(obj.Active)
+	stream.WriteRaw(`"`)
+	stream.WriteMore()
+	stream.WriteObjectField("id")
+	stream.WriteRaw(`"`)
+	stream.WriteInt64(obj.ID)
+	stream.WriteRaw(`"`)
+	stream.WriteMore()
+	stream.WriteObjectField("code")
+	{
+		inner := stream.Pool().BorrowStream(nil)
+		inner.WriteStringValue(obj.Code)
+		stream.WriteString(string(inner.Buffer()))
+		stream.Pool().ReturnStream(inner)
+	}
+	stream.WriteMore()
+	stream.WriteObjectField("level")
+	stream.WriteVal(&obj.Level)
+	stream.WriteMore()
+	stream.WriteObjectField("home")
+	stream.WriteVal(&obj.Home)
+	if obj.Work != nil {
+		stream.WriteMore()
+		stream.WriteObjectField("work")
+		stream.WriteVal(&obj.Work)
+	}
+	stream.WriteMore()
+	stream.WriteObjectField("tags")
+	stream.WriteVal(&obj.Tags)
+	if len(obj.Labels) != 0 {
+		stream.WriteMore()
+		stream.WriteObjectField("labels")
+		stream.WriteVal(&obj.Labels)
+	}
+	if obj.Extra != nil {
+		stream.WriteMore()
+		stream.WriteObjectField("extra")
+		stream.WriteVal(&obj.Extra)
+	}
+	if len(obj.Raw) != 0 {
+		stream.WriteMore()
+		stream.WriteObjectField("raw")
+		stream.WriteVal(&obj.Raw)
+	}
+	if obj.Number != "" {
+		stream.WriteMore()
+		stream.WriteObjectField("number")
+		stream.WriteVal(&obj.Number)
+	}
+	if obj.Email.IsPresent() {
+		stream.WriteMore()
+		stream.WriteObjectField("email")
+		stream.WriteVal(&obj.Email)
+	}
+	if obj.Phone.IsSet() {
+		stream.WriteMore()
+		stream.WriteObjectField("phone")
+		stream.WriteVal(&obj.Phone)
+	}
+	stream.WriteMore()
+	stream.WriteObjectField("revision")
+	stream.WriteInt(obj.Revision)
+	stream.WriteMore()
+	stream.WriteObjectField("note")
+	stream.WriteStringValue(obj.Note)
+	stream.WriteMore()
+	stream.WriteObjectField("Untagged")
+	stream.WriteUint8(obj.Untagged)
+	if obj.Timestamp != nil {
+		stream.WriteMore()
+		stream.WriteObjectField("Timestamp")
+		stream.WriteVal(&obj.Timestamp)
+	}
+	stream.WriteObjectEnd()
+	return nil
+}
+
+// UnmarshalJSONFrom reads obj from the iterator, following the struct tags of Person
+func (obj *Person) UnmarshalJSONFrom(iter *jsoniter.Iterator) error {
+	iter.ReadObjectRawCB(func(iter *jsoniter.Iterator, raw jsoniter.RawString) bool {
+		field, _ := raw.Bytes()
+		if raw.ContainsEscapes() {
+			field = []byte(raw.String())
+		}
+		switch string(field) {
+		case "created":
+			iter.ReadVal(&obj.Audit.Created)
+		case "version":
+			if !iter.ReadNil() {
+				obj.Audit.Version = iter.ReadInt()
+			}
+		case "Note":
+			obj.Audit.Note = iter.ReadString()
+		case "name", "full_name":
+			obj.Name = iter.ReadString()
+		case "nick", "nickname":
+			obj.Nick = iter.ReadString()
+		case "age":
+			if !iter.ReadNil() {
+				obj.Age = iter.ReadInt()
+			}
+		case "height":
+			if !iter.ReadNil() {
+				obj.Height = iter.ReadFloat32()
+			}
+		case "score":
+			if !iter.ReadNil() {
+				obj.Score = Score(iter.ReadFloat64())
+			}
+		case "active":
+			iter.ReadQuoted(func() {
+				if !iter.ReadNil() {
+					obj.Active = iter.ReadBool()
+				}
+			})
+		case "id":
+			iter.ReadQuoted(func() {
+				if !iter.ReadNil() {
+					obj.ID = iter.ReadInt64()
+				}
+			})
+		case "code":
+			{
+				inner := iter.Pool().BorrowIterator([]byte(iter.ReadString()))
+				obj.Code = inner.ReadString()
+				iter.Pool().ReturnIterator(inner)
+			}
+		case "level":
+			iter.ReadVal(&obj.Level)
+		case "home":
+			iter.ReadVal(&obj.Home)
+		case "work":
+			iter.ReadVal(&obj.Work)
+		case "tags":
+			iter.ReadVal(&obj.Tags)
+		case "labels":
+			iter.ReadVal(&obj.Labels)
+		case "extra":
+			iter.ReadVal(&obj.Extra)
+		case "raw":
+			iter.ReadVal(&obj.Raw)
+		case "number":
+			iter.ReadVal(&obj.Number)
+		case "email":
+			iter.ReadVal(&obj.Email)
+		case "phone":
+			iter.ReadVal(&obj.Phone)
+		case "password":
+			obj.Password = iter.ReadString()
+		case "revision":
+			iter.SkipReadOnlyField(string(field))
+		case "note":
+			obj.Note = iter.ReadString()
+		case "Untagged":
+			if !iter.ReadNil() {
+				obj.Untagged = iter.ReadUint8()
+			}
+		case "Timestamp":
+			iter.ReadVal(&obj.Timestamp)
+		default:
+			switch name := string(field); {
+			case iter.MatchField(name, "created"):
+				iter.ReadVal(&obj.Audit.Created)
+			case iter.MatchField(name, "version"):
+				if !iter.ReadNil() {
+					obj.Audit.Version = iter.ReadInt()
+				}
+			case iter.MatchField(name, "name"), iter.MatchField(name, "full_name"):
+				obj.Name = iter.ReadString()
+			case iter.MatchField(name, "nick"), iter.MatchField(name, "nickname"):
+				obj.Nick = iter.ReadString()
+			case iter.MatchField(name, "age"):
+				if !iter.ReadNil() {
+					obj.Age = iter.ReadInt()
+				}
+			case iter.MatchField(name, "height"):
+				if !iter.ReadNil() {
+					obj.Height = iter.ReadFloat32()
+				}
+			case iter.MatchField(name, "score"):
+				if !iter.ReadNil() {
+					obj.Score = Score(iter.ReadFloat64())
+				}
+			case iter.MatchField(name, "active"):
+				iter.ReadQuoted(func() {
+					if !iter.ReadNil() {
+						obj.Active = iter.ReadBool()
+					}
+				})
+			case iter.MatchField(name, "id"):
+				iter.ReadQuoted(func() {
+					if !iter.ReadNil() {
+						obj.ID = iter.ReadInt64()
+					}
+				})
+			case iter.MatchField(name, "code"):
+				{
+					inner := iter.Pool().BorrowIterator([]byte(iter.ReadString()))
+					obj.Code = inner.ReadString()
+					iter.Pool().ReturnIterator(inner)
+				}
+			case iter.MatchField(name, "level"):
+				iter.ReadVal(&obj.Level)
+			case iter.MatchField(name, "home"):
+				iter.ReadVal(&obj.Home)
+			case iter.MatchField(name, "work"):
+				iter.ReadVal(&obj.Work)
+			case iter.MatchField(name, "tags"):
+				iter.ReadVal(&obj.Tags)
+			case iter.MatchField(name, "labels"):
+				iter.ReadVal(&obj.Labels)
+			case iter.MatchField(name, "extra"):
+				iter.ReadVal(&obj.Extra)
+			case iter.MatchField(name, "raw"):
+				iter.ReadVal(&obj.Raw)
+			case iter.MatchField(name, "number"):
+				iter.ReadVal(&obj.Number)
+			case iter.MatchField(name, "email"):
+				iter.ReadVal(&obj.Email)
+			case iter.MatchField(name, "phone"):
+				iter.ReadVal(&obj.Phone)
+			case iter.MatchField(name, "password"):
+				obj.Password = iter.ReadString()
+			case iter.MatchField(name, "revision"):
+				iter.SkipReadOnlyField(string(field))
+			case iter.MatchField(name, "note"):
+				obj.Note = iter.ReadString()
+			case iter.MatchField(name, "Note"):
+				obj.Audit.Note = iter.ReadString()
+			case iter.MatchField(name, "Untagged"):
+				if !iter.ReadNil() {
+					obj.Untagged = iter.ReadUint8()
+				}
+			case iter.MatchField(name, "Timestamp"):
+				iter.ReadVal(&obj.Timestamp)
+			default:
+				iter.SkipUnknownField(name)
+			}
+		}
+		return true
+	})
+	return nil
+}
+
+// MarshalJSONTo writes obj into the stream, following the struct tags of Address
+func (obj *Address) MarshalJSONTo(stream *jsoniter.Stream) error {
+	stream.WriteObjectStart()
+	stream.WriteObjectField("street")
+	stream.WriteStringValue(obj.Street)
+	if obj.City != "" {
+		stream.WriteMore()
+		stream.WriteObjectField("city")
+		stream.WriteStringValue(obj.City)
+	}
+	stream.WriteMore()
+	stream.WriteObjectField("zip")
+	stream.WriteRaw(`"`)
+	stream.WriteInt(obj.Zip)
+	stream.WriteRaw(`"`)
+	stream.WriteObjectEnd()
+	return nil
+}
+
+// UnmarshalJSONFrom reads obj from the iterator, following the struct tags of Address
+func (obj *Address) UnmarshalJSONFrom(iter *jsoniter.Iterator) error {
+	iter.ReadObjectRawCB(func(iter *jsoniter.Iterator, raw jsoniter.RawString) bool {
+		field, _ := raw.Bytes()
+		if raw.ContainsEscapes() {
+			field = []byte(raw.String())
+		}
+		switch string(field) {
+		case "street":
+			obj.Street = iter.ReadString()
+		case "city":
+			obj.City = iter.ReadString()
+		case "zip":
+			iter.ReadQuoted(func() {
+				if !iter.ReadNil() {
+					obj.Zip = iter.ReadInt()
+				}
+			})
+		default:
+			switch name := string(field); {
+			case iter.MatchField(name, "street"):
+				obj.Street = iter.ReadString()
+			case iter.MatchField(name, "city"):
+				obj.City = iter.ReadString()
+			case iter.MatchField(name, "zip"):
+				iter.ReadQuoted(func() {
+					if !iter.ReadNil() {
+						obj.Zip = iter.ReadInt()
+					}
+				})
+			default:
+				iter.SkipUnknownField(name)
+			}
+		}
+		return true
+	})
+	return nil
+}
+
+// MarshalJSONTo writes obj into the stream, following the struct tags of Empty
+func (obj *Empty) MarshalJSONTo(stream *jsoniter.Stream) error {
+	stream.WriteEmptyObject()
+	return nil
+}
+
+// UnmarshalJSONFrom reads obj from the iterator, following the struct tags of Empty
+func (obj *Empty) UnmarshalJSONFrom(iter *jsoniter.Iterator) error {
+	iter.ReadObjectRawCB(func(iter *jsoniter.Iterator, raw jsoniter.RawString) bool {
+		field, _ := raw.Bytes()
+		if raw.ContainsEscapes() {
+			field = []byte(raw.String())
+		}
+		iter.SkipUnknownField(string(field))
+		return true
+	})
+	return nil
+}
@@ -0,0 +1,72 @@
+// Package sample holds the types the codecs of jsoniter-gen are tested with
+package sample
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/json-iterator/go"
+)
+
+//go:generate go run ../.. -type Person,Address,Empty
+
+// Level is encoded by its MarshalText
+type Level int
+
+func (level Level) MarshalText() ([]byte, error) {
+	return []byte(strings.Repeat("*", int(level))), nil
+}
+
+func (level *Level) UnmarshalText(text []byte) error {
+	*level = Level(len(text))
+	return nil
+}
+
+// Score is a named basic type without codec methods
+type Score float64
+
+type Address struct {
+	Street string `json:"street"`
+	City   string `json:"city,omitempty"`
+	Zip    int    `json:"zip,string"`
+}
+
+type Audit struct {
+	Created time.Time `json:"created"`
+	Version int       `json:"version"`
+	Note    string
+}
+
+type Person struct {
+	Audit
+	Name      string                    `json:"name,alias=full_name"`
+	Nick      string                    `json:"nick,omitempty,alias=nickname"`
+	Age       int                       `json:"age,omitempty"`
+	Height    float32                   `json:"height"`
+	Score     Score                     `json:"score,omitempty"`
+	Active    bool                      `json:"active,string"`
+	ID        int64                     `json:"id,string"`
+	Code      string                    `json:"code,string"`
+	Level     Level                     `json:"level"`
+	Home      Address                   `json:"home"`
+	Work      *Address                  `json:"work,omitempty"`
+	Tags      []string                  `json:"tags"`
+	Labels    map[string]string         `json:"labels,omitempty"`
+	Extra     interface{}               `json:"extra,omitempty"`
+	Raw       json.RawMessage           `json:"raw,omitempty"`
+	Number    json.Number               `json:"number,omitempty"`
+	Email     jsoniter.Nullable[string] `json:"email"`
+	Phone     jsoniter.Optional[string] `json:"phone"`
+	Password  string                    `json:"password,writeonly"`
+	Revision  int                       `json:"revision,readonly"`
+	Note      string                    `json:"note"`
+	Ignored   string                    `json:"-"`
+	private   string
+	Untagged  uint8
+	Timestamp *time.Time `json:",omitempty"`
+}
+
+type Empty struct {
+	hidden int
+}
@@ -0,0 +1,147 @@
+package sample
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+// the converted types drop the generated methods, so they are encoded by reflection
+type reflectivePerson Person
+type reflectiveAddress Address
+
+func samplePeople() []Person {
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	return []Person{
+		{},
+		{
+			Audit:     Audit{Created: created, Version: 3, Note: "audited"},
+			Name:      "Ada <Lovelace>",
+			Nick:      "ada",
+			Age:       36,
+			Height:    1.65,
+			Score:     99.5,
+			Active:    true,
+			ID:        1 << 60,
+			Code:      `a"b`,
+			Level:     3,
+			Home:      Address{Street: "1 Main St", City: "London", Zip: 12345},
+			Work:      &Address{Street: "2 Side St"},
+			Tags:      []string{"math", "poetry"},
+			Labels:    map[string]string{"a": "1"},
+			Extra:     map[string]interface{}{"x": []interface{}{1.5, "y"}},
+			Raw:       json.RawMessage(`{"k": [1, 2]}`),
+			Number:    "12.50",
+			Email:     jsoniter.Null[string](),
+			Phone:     jsoniter.NewOptional("555"),
+			Password:  "secret",
+			Revision:  7,
+			Note:      "note",
+			Ignored:   "ignored",
+			private:   "private",
+			Untagged:  255,
+			Timestamp: &created,
+		},
+	}
+}
+
+func Test_encode_parity(t *testing.T) {
+	should := require.New(t)
+	apis := []jsoniter.API{
+		jsoniter.ConfigDefault,
+		jsoniter.ConfigCompatibleWithStandardLibrary,
+		jsoniter.ConfigFastest,
+		jsoniter.Config{IndentionStep: 2, SortMapKeys: true}.Froze(),
+	}
+	for _, api := range apis {
+		for _, person := range samplePeople() {
+			person := person
+			generated, err := api.Marshal(&person)
+			should.NoError(err)
+			reflective, err := api.Marshal((*reflectivePerson)(&person))
+			should.NoError(err)
+			should.Equal(string(reflective), string(generated))
+			generated, err = api.Marshal([]*Address{&person.Home, nil})
+			should.NoError(err)
+			reflective, err = api.Marshal([]*reflectiveAddress{(*reflectiveAddress)(&person.Home), nil})
+			should.NoError(err)
+			should.Equal(string(reflective), string(generated))
+		}
+	}
+	output, err := jsoniter.Marshal(&Empty{})
+	should.NoError(err)
+	should.Equal(`{}`, string(output))
+}
+
+func Test_decode_parity(t *testing.T) {
+	should := require.New(t)
+	inputs := []string{
+		`{}`,
+		`null`,
+		`{"created":"2020-01-02T03:04:05Z","version":3,"Note":"a","name":"n","nick":"k","age":null,
+		"height":1.5,"score":2,"active":"true","id":"-12","code":"\"c\"","level":"**","home":{"street":"s","zip":"1"},
+		"work":{"city":"c"},"tags":["t"],"labels":{"a":"b"},"extra":[1,{"x":null}],"raw":{"r": 1},"number":1.25,
+		"email":"e","phone":null,"password":"p","revision":4,"note":"b","Untagged":8,"Timestamp":"2021-01-01T00:00:00Z","unknown":[1,2]}`,
+		`{"full_name":"alias","nickname":"alias","NAME":"folded","Version":5,"note":"exact","NOTE":"folded","age":12}`,
+		`{"work":null,"tags":null,"Timestamp":null,"email":null,"phone":"p"}`,
+	}
+	apis := []jsoniter.API{
+		jsoniter.ConfigDefault,
+		jsoniter.Config{CaseSensitive: true}.Froze(),
+		jsoniter.Config{FieldMatching: jsoniter.FieldMatchIgnoreSeparators}.Froze(),
+	}
+	for _, api := range apis {
+		for _, input := range inputs {
+			var generated Person
+			var reflective reflectivePerson
+			should.NoError(api.UnmarshalFromString(input, &generated), input)
+			should.NoError(api.UnmarshalFromString(input, &reflective), input)
+			should.Equal(Person(reflective), generated, input)
+		}
+	}
+}
+
+func Test_decode_errors(t *testing.T) {
+	should := require.New(t)
+	strict := jsoniter.Config{DisallowUnknownFields: true, DisallowReadOnlyFields: true}.Froze()
+	for _, input := range []string{`{"unknown":1}`, `{"revision":1}`, `{"id":12}`, `[]`, `{"age":"x"}`} {
+		var generated Person
+		var reflective reflectivePerson
+		generatedErr := strict.UnmarshalFromString(input, &generated)
+		reflectiveErr := strict.UnmarshalFromString(input, &reflective)
+		should.Error(generatedErr, input)
+		should.Error(reflectiveErr, input)
+	}
+}
+
+func Test_generated_codecs_are_used(t *testing.T) {
+	should := require.New(t)
+	var _ jsoniter.StreamMarshaler = &Person{}
+	var _ jsoniter.IteratorUnmarshaler = &Person{}
+	person := samplePeople()[1]
+	stream := jsoniter.ConfigDefault.BorrowStream(nil)
+	defer jsoniter.ConfigDefault.ReturnStream(stream)
+	should.NoError(person.Home.MarshalJSONTo(stream))
+	should.Equal(`{"street":"1 Main St","city":"London","zip":"12345"}`, string(stream.Buffer()))
+}
+
+func Test_typed_codecs_use_generated_codecs(t *testing.T) {
+	should := require.New(t)
+	encoder := jsoniter.EncoderFor[Address](jsoniter.ConfigDefault)
+	address := samplePeople()[1].Home
+	output, err := encoder.Marshal(&address)
+	should.NoError(err)
+	should.Equal(`{"street":"1 Main St","city":"London","zip":"12345"}`, string(output))
+	decoded, err := jsoniter.UnmarshalAs[Address](output)
+	should.NoError(err)
+	should.Equal(address, decoded)
+	stream := jsoniter.ConfigDefault.BorrowStream(nil)
+	defer jsoniter.ConfigDefault.ReturnStream(stream)
+	should.Equal(float64(0), testing.AllocsPerRun(100, func() {
+		stream.Reset(nil)
+		encoder.Encode(stream, &address)
+	}))
+}
@@ -0,0 +1,62 @@
+// Command jsoniter-gen generates MarshalJSONTo and UnmarshalJSONFrom methods for struct types,
+// which jsoniter then uses instead of building codecs by reflection.
+//
+// Usage:
+//
+//	jsoniter-gen -type Person,Address [-output person_jsoniter.go] [dir]
+//
+// or in a source file of the package:
+//
+//	//go:generate jsoniter-gen -type Person,Address
+//
+// The struct tags are honoured as the reflection codecs do: renaming, "-", omitempty, string,
+// readonly, writeonly, alias= and the fields promoted from embedded structs.
+// Tags needing runtime decoration (format:, precision=, redact, groups) and FieldSet fields
+// are rejected, keep those types on the reflection path.
+//
+// The methods have pointer receivers, so as with MarshalJSON, a struct value at the root
+// is encoded by reflection. Config dependent behaviour of strings, floats, field matching,
+// unknown and read-only fields follows the config; extensions, naming strategies,
+// serialization groups, field masks, redaction and projections do not apply to generated types.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	typeNames := flag.String("type", "", "comma separated struct type names, required")
+	output := flag.String("output", "", "output file name, default <dir>/<first type>_jsoniter.go")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: jsoniter-gen -type T[,T...] [-output file] [dir]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *typeNames == "" || flag.NArg() > 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	dir := "."
+	if flag.NArg() == 1 {
+		dir = flag.Arg(0)
+	}
+	types := strings.Split(*typeNames, ",")
+	src, err := Generate(dir, types)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "jsoniter-gen: %v\n", err)
+		os.Exit(1)
+	}
+	outputName := *output
+	if outputName == "" {
+		outputName = filepath.Join(dir, strings.ToLower(types[0])+"_jsoniter.go")
+	}
+	if err := ioutil.WriteFile(outputName, src, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "jsoniter-gen: %v\n", err)
+		os.Exit(1)
+	}
+}
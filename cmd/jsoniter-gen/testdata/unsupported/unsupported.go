@@ -0,0 +1,25 @@
+package unsupported
+
+import "time"
+
+type NotStruct int
+
+type Redacted struct {
+	Secret string `json:"secret,redact"`
+}
+
+type Formatted struct {
+	Created time.Time `json:"created,format:unix"`
+}
+
+type Grouped struct {
+	Internal string `json:"internal" groups:"admin"`
+}
+
+type Base struct {
+	ID int
+}
+
+type EmbeddedPointer struct {
+	*Base
+}
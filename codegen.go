@@ -0,0 +1,81 @@
+package jsoniter
+
+// The methods below are used by the code jsoniter-gen generates,
+// to behave as the reflection based codecs do under the same config.
+
+// WriteStringValue writes a string as the config encodes strings, escaping HTML if EscapeHTML is set
+func (stream *Stream) WriteStringValue(val string) {
+	if stream.cfg.configBeforeFrozen.EscapeHTML {
+		stream.WriteStringWithHTMLEscaped(val)
+	} else {
+		stream.WriteString(val)
+	}
+}
+
+// WriteFloat32Value writes a float32 as the config encodes it, with 6 digits if MarshalFloatWith6Digits is set
+func (stream *Stream) WriteFloat32Value(val float32) {
+	if stream.cfg.configBeforeFrozen.MarshalFloatWith6Digits {
+		stream.WriteFloat32Lossy(val)
+	} else {
+		stream.WriteFloat32(val)
+	}
+}
+
+// WriteFloat64Value writes a float64 as the config encodes it, with 6 digits if MarshalFloatWith6Digits is set
+func (stream *Stream) WriteFloat64Value(val float64) {
+	if stream.cfg.configBeforeFrozen.MarshalFloatWith6Digits {
+		stream.WriteFloat64Lossy(val)
+	} else {
+		stream.WriteFloat64(val)
+	}
+}
+
+// MatchField reports whether the object field read from the input is the struct field named name
+// under the field matching of the config. Exact matches should be looked up before.
+func (iter *Iterator) MatchField(field, name string) bool {
+	matching := iter.cfg.fieldMatching
+	return matching != FieldMatchExact && canonicalFieldName(field, matching) == canonicalFieldName(name, matching)
+}
+
+// SkipUnknownField skips the value of an object field matching no struct field,
+// or reports it if the config disallows unknown fields
+func (iter *Iterator) SkipUnknownField(field string) {
+	if iter.cfg.disallowUnknownFields {
+		iter.ReportError("ReadObject", "found unknown field: "+field)
+		return
+	}
+	iter.Skip()
+}
+
+// SkipReadOnlyField skips the value of a readonly struct field,
+// reported if the config disallows read-only fields, unknown otherwise
+func (iter *Iterator) SkipReadOnlyField(field string) {
+	if iter.cfg.disallowReadOnlyFields {
+		iter.Skip()
+		iter.ReportError("readOnlyFieldDecoder", "field is read-only")
+		return
+	}
+	iter.SkipUnknownField(field)
+}
+
+// ReadQuoted reads a value quoted in a JSON string, as the string tag option does.
+// read is called inside the quotes, or on null.
+func (iter *Iterator) ReadQuoted(read func()) {
+	if iter.WhatIsNext() == NilValue {
+		read()
+		return
+	}
+	c := iter.nextToken()
+	if c != '"' {
+		iter.ReportError("ReadQuoted", `expect ", but found `+string([]byte{c}))
+		return
+	}
+	read()
+	if iter.Error != nil {
+		return
+	}
+	c = iter.readByte()
+	if c != '"' {
+		iter.ReportError("ReadQuoted", `expect ", but found `+string([]byte{c}))
+	}
+}
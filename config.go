@@ -1,9 +1,12 @@
 package jsoniter
 
 import (
+	"container/list"
 	"encoding/json"
 	"io"
 	"reflect"
+	"sort"
+	"strings"
 	"sync"
 	"unsafe"
 
@@ -20,12 +23,20 @@ type Config struct {
 	SortMapKeys                   bool
 	UseNumber                     bool
 	DisallowUnknownFields         bool
+	DisallowReadOnlyFields        bool
 	TagKey                        string
 	OnlyTaggedField               bool
 	ValidateJsonRawMessage        bool
+	ValidateMarshalerOutput       bool // invalid json.Marshaler output fails the encoding instead of being written
 	ObjectFieldMustBeSimpleString bool
 	CaseSensitive                 bool
 	InvalidFloatToNil             bool
+	Groups                        string // comma separated groups; fields whose groups:"..." tag names none of them are skipped when encoding
+	DecodeByGroups                bool   // also skip the fields out of Groups when decoding
+	Redaction                     RedactionPolicy
+	RedactionKey                  string // secret keying the HMAC-SHA256 written by RedactHash
+	Projection                    string // comma separated JSON paths to decode, others are skipped
+	FieldMatching                 FieldMatching
 }
 
 // API the public interface of this package.
@@ -54,9 +65,10 @@ var ConfigDefault = Config{
 
 // ConfigCompatibleWithStandardLibrary tries to be 100% compatible with standard library behavior
 var ConfigCompatibleWithStandardLibrary = Config{
-	EscapeHTML:             true,
-	SortMapKeys:            true,
-	ValidateJsonRawMessage: true,
+	EscapeHTML:              true,
+	SortMapKeys:             true,
+	ValidateJsonRawMessage:  true,
+	ValidateMarshalerOutput: true,
 }.Froze()
 
 // ConfigFastest marshals float with only 6 digits precision
@@ -73,6 +85,7 @@ type frozenConfig struct {
 	objectFieldMustBeSimpleString bool
 	onlyTaggedField               bool
 	disallowUnknownFields         bool
+	disallowReadOnlyFields        bool
 	decoderCache                  *concurrent.Map
 	encoderCache                  *concurrent.Map
 	encoderExtension              Extension
@@ -80,13 +93,27 @@ type frozenConfig struct {
 	extraExtensions               []Extension
 	streamPool                    *sync.Pool
 	iteratorPool                  *sync.Pool
-	caseSensitive                 bool
+	fieldMatching                 FieldMatching
 	invalidFloatToNil             bool
+	validateMarshalerOutput       bool
+	groups                        map[string]bool
+	decodeByGroups                bool
+	redaction                     RedactionPolicy
+	redactionKey                  []byte
+	projection                    *FieldMask
+	registeredDecoders            DecoderExtension
+	registeredEncoders            EncoderExtension
+	fieldDecoders                 map[fieldCodecKey]ValDecoder
+	fieldEncoders                 map[fieldCodecKey]ValEncoder
+	derivedCache                  *concurrent.Map
+	projectionCache               *derivedLRU
 }
 
 func (cfg *frozenConfig) initCache() {
 	cfg.decoderCache = concurrent.NewMap()
 	cfg.encoderCache = concurrent.NewMap()
+	cfg.derivedCache = concurrent.NewMap()
+	cfg.projectionCache = newDerivedLRU(projectionCacheSize)
 }
 
 func (cfg *frozenConfig) addDecoderToCache(cacheKey uintptr, decoder ValDecoder) {
@@ -113,20 +140,6 @@ func (cfg *frozenConfig) getEncoderFromCache(cacheKey uintptr) ValEncoder {
 	return nil
 }
 
-var cfgCache = concurrent.NewMap()
-
-func getFrozenConfigFromCache(cfg Config) *frozenConfig {
-	obj, found := cfgCache.Load(cfg)
-	if found {
-		return obj.(*frozenConfig)
-	}
-	return nil
-}
-
-func addFrozenConfigToCache(cfg Config, frozenConfig *frozenConfig) {
-	cfgCache.Store(cfg, frozenConfig)
-}
-
 // Froze forge API from config
 func (cfg Config) Froze() API {
 	api := &frozenConfig{
@@ -135,8 +148,31 @@ func (cfg Config) Froze() API {
 		objectFieldMustBeSimpleString: cfg.ObjectFieldMustBeSimpleString,
 		onlyTaggedField:               cfg.OnlyTaggedField,
 		disallowUnknownFields:         cfg.DisallowUnknownFields,
-		caseSensitive:                 cfg.CaseSensitive,
+		disallowReadOnlyFields:        cfg.DisallowReadOnlyFields,
+		fieldMatching:                 cfg.FieldMatching,
 		invalidFloatToNil:             cfg.InvalidFloatToNil,
+		validateMarshalerOutput:       cfg.ValidateMarshalerOutput,
+		decodeByGroups:                cfg.DecodeByGroups,
+		redaction:                     cfg.Redaction,
+	}
+	if api.fieldMatching == FieldMatchDefault {
+		if cfg.CaseSensitive {
+			api.fieldMatching = FieldMatchExact
+		} else {
+			api.fieldMatching = FieldMatchUnicodeFold
+		}
+	}
+	if cfg.RedactionKey != "" {
+		api.redactionKey = []byte(cfg.RedactionKey)
+	}
+	if cfg.Projection != "" {
+		api.projection = IncludeFields(strings.Split(cfg.Projection, ",")...)
+	}
+	if cfg.Groups != "" {
+		api.groups = map[string]bool{}
+		for _, group := range strings.Split(cfg.Groups, ",") {
+			api.groups[strings.TrimSpace(group)] = true
+		}
 	}
 	api.streamPool = &sync.Pool{
 		New: func() interface{} {
@@ -169,29 +205,80 @@ func (cfg Config) Froze() API {
 	return api
 }
 
-func (cfg Config) frozeWithCacheReuse(extraExtensions []Extension) *frozenConfig {
-	api := getFrozenConfigFromCache(cfg)
-	if api != nil {
-		return api
+// deriveWithCacheReuse returns the API of newCfg derived from cfg, cached by cfg until a codec is registered on it
+func (cfg *frozenConfig) deriveWithCacheReuse(newCfg Config) *frozenConfig {
+	obj, found := cfg.derivedCache.Load(newCfg)
+	if found {
+		return obj.(*frozenConfig)
 	}
-	api = cfg.Froze().(*frozenConfig)
-	for _, extension := range extraExtensions {
+	api := cfg.derive(newCfg)
+	cfg.derivedCache.Store(newCfg, api)
+	return api
+}
+
+// derive returns the API of newCfg with the extensions and codecs registered on cfg
+// the projected APIs cached by API, the least recently used are dropped beyond, as projections can come from the input
+const projectionCacheSize = 64
+
+// derivedLRU caches the APIs derived by a Config, up to size of them
+type derivedLRU struct {
+	mutex    sync.Mutex
+	size     int
+	elements map[Config]*list.Element
+	order    *list.List // of *derivedAPI, the most recently used first
+}
+
+type derivedAPI struct {
+	cfg Config
+	api *frozenConfig
+}
+
+func newDerivedLRU(size int) *derivedLRU {
+	return &derivedLRU{size: size, elements: map[Config]*list.Element{}, order: list.New()}
+}
+
+// get returns the API of cfg, derived and added if not found
+func (lru *derivedLRU) get(cfg Config, derive func(Config) *frozenConfig) *frozenConfig {
+	lru.mutex.Lock()
+	defer lru.mutex.Unlock()
+	if element, found := lru.elements[cfg]; found {
+		lru.order.MoveToFront(element)
+		return element.Value.(*derivedAPI).api
+	}
+	api := derive(cfg)
+	lru.elements[cfg] = lru.order.PushFront(&derivedAPI{cfg, api})
+	if lru.order.Len() > lru.size {
+		oldest := lru.order.Remove(lru.order.Back()).(*derivedAPI)
+		delete(lru.elements, oldest.cfg)
+	}
+	return api
+}
+
+func (cfg *frozenConfig) derive(newCfg Config) *frozenConfig {
+	api := newCfg.Froze().(*frozenConfig)
+	for _, extension := range cfg.extraExtensions {
 		api.RegisterExtension(extension)
 	}
-	addFrozenConfigToCache(cfg, api)
+	for typ, decoder := range cfg.registeredDecoders {
+		api.decoderExtension.(DecoderExtension)[typ] = decoder
+	}
+	for typ, encoder := range cfg.registeredEncoders {
+		api.encoderExtension.(EncoderExtension)[typ] = encoder
+	}
+	api.registeredDecoders = cfg.registeredDecoders
+	api.registeredEncoders = cfg.registeredEncoders
+	api.fieldDecoders = cfg.fieldDecoders
+	api.fieldEncoders = cfg.fieldEncoders
 	return api
 }
 
 func (cfg *frozenConfig) validateJsonRawMessage(extension EncoderExtension) {
 	encoder := &funcEncoder{func(ptr unsafe.Pointer, stream *Stream) {
 		rawMessage := *(*json.RawMessage)(ptr)
-		iter := cfg.BorrowIterator([]byte(rawMessage))
-		defer cfg.ReturnIterator(iter)
-		iter.Read()
-		if iter.Error != nil && iter.Error != io.EOF {
+		if cfg.validValue(rawMessage) != nil {
 			stream.WriteRaw("null")
 		} else {
-			stream.WriteRaw(string(rawMessage))
+			stream.writeRawValue(rawMessage)
 		}
 	}, func(ptr unsafe.Pointer) bool {
 		return len(*((*json.RawMessage)(ptr))) == 0
@@ -320,7 +407,43 @@ func (cfg *frozenConfig) MarshalIndent(v interface{}, prefix, indent string) ([]
 	}
 	newCfg := cfg.configBeforeFrozen
 	newCfg.IndentionStep = len(indent)
-	return newCfg.frozeWithCacheReuse(cfg.extraExtensions).Marshal(v)
+	return cfg.deriveWithCacheReuse(newCfg).Marshal(v)
+}
+
+// WithGroups returns the API of api limited to the given serialization groups.
+// The API is cached, the codecs are compiled once per group set.
+func WithGroups(api API, groups ...string) API {
+	cfg := api.(*frozenConfig)
+	groups = append([]string(nil), groups...)
+	sort.Strings(groups)
+	newCfg := cfg.configBeforeFrozen
+	newCfg.Groups = strings.Join(groups, ",")
+	return cfg.deriveWithCacheReuse(newCfg)
+}
+
+// WithProjection returns the API of api decoding only the given dot separated JSON paths, like "owner.email".
+// The other values are skipped without being decoded, the encoding is unchanged.
+// Paths go through arrays and pointers. Maps with string keys are projected on their keys, others are decoded whole.
+// The API is cached by api for the same set of paths, in any order, up to a number of projections
+// as projections can come from the input.
+func WithProjection(api API, paths ...string) API {
+	cfg := api.(*frozenConfig)
+	normalized := []string{}
+	for _, path := range strings.Split(strings.Join(paths, ","), ",") {
+		path = strings.TrimSpace(path)
+		if path != "" {
+			normalized = append(normalized, path)
+		}
+	}
+	sort.Strings(normalized)
+	for i := len(normalized) - 1; i > 0; i-- {
+		if normalized[i] == normalized[i-1] {
+			normalized = append(normalized[:i], normalized[i+1:]...)
+		}
+	}
+	newCfg := cfg.configBeforeFrozen
+	newCfg.Projection = strings.Join(normalized, ",")
+	return cfg.projectionCache.get(newCfg, cfg.derive)
 }
 
 func (cfg *frozenConfig) UnmarshalFromString(str string, v interface{}) error {
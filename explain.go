@@ -0,0 +1,421 @@
+package jsoniter
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/modern-go/reflect2"
+)
+
+// Explanation describes the codecs built for a type, as returned by Explain
+type Explanation struct {
+	Type          string
+	Decoder       string              // type of the decoder, for a struct the decoder variant chosen
+	Encoder       string              // type of the encoder
+	DecoderSource string              // extension or registration supplying the decoder, empty if built by jsoniter
+	EncoderSource string              // extension or registration supplying the encoder, empty if built by jsoniter
+	DecoratedBy   []string            // extensions decorating the decoder or the encoder
+	Elem          *Explanation        // the type pointed to, nil if not a pointer
+	Fields        []*FieldExplanation // the bindings, nil if the codecs are not built from the struct fields
+	Removed       []string            // fields whose binding an extension removed, as "Field by extension"
+}
+
+// FieldExplanation describes a binding of a struct field
+type FieldExplanation struct {
+	Field         string   // Go field name, dotted path for a field promoted from an embedded struct
+	Type          string   // Go type of the field
+	FromNames     []string // names decoded into the field, empty if never decoded
+	ToNames       []string // names the field is encoded as, empty if never encoded
+	Conflicts     []string // names lost to another binding or left out of the projection
+	OmitEmpty     bool
+	StringMode    bool
+	Decoder       string       // type of the decoder of the field value
+	Encoder       string       // type of the encoder of the field value
+	DecoderSource string       // extension or registration supplying the decoder, empty if built by jsoniter
+	EncoderSource string       // extension or registration supplying the encoder, empty if built by jsoniter
+	DecoratedBy   []string     // extensions decorating the decoder or the encoder
+	UpdatedBy     []string     // extensions changing the binding in UpdateStructDescriptor
+	Struct        *Explanation // the struct the field holds, directly or as elements, nil if explained above
+}
+
+// Explain describes the codecs the config builds for typ: the bindings of a struct with their names and options,
+// which extension or registration supplied each codec, and the struct decoder variant chosen.
+// The codecs are built again for the explanation, the cached ones are not changed.
+func Explain(api API, typ reflect.Type) *Explanation {
+	cfg := api.(*frozenConfig)
+	ctx := &ctx{
+		frozenConfig: cfg,
+		prefix:       "",
+		decoders:     map[reflect2.Type]ValDecoder{},
+		projected:    map[projectedType]ValDecoder{},
+		encoders:     map[reflect2.Type]ValEncoder{},
+		projection:   cfg.projection,
+		sources:      newCodecSources(),
+	}
+	return ctx.explain(reflect2.Type2(typ), map[reflect2.Type]bool{})
+}
+
+func (b *ctx) explain(typ reflect2.Type, explained map[reflect2.Type]bool) *Explanation {
+	explained[typ] = true
+	decoder := decoderOfType(b, typ)
+	if placeholder, isPlaceholder := decoder.(*placeholderDecoder); isPlaceholder {
+		decoder = placeholder.decoder
+	}
+	encoder := encoderOfType(b, typ)
+	if placeholder, isPlaceholder := encoder.(*placeholderEncoder); isPlaceholder {
+		encoder = placeholder.encoder
+	}
+	explanation := &Explanation{
+		Type:          typ.String(),
+		Decoder:       codecName(decoder),
+		Encoder:       codecName(encoder),
+		DecoderSource: b.sources.decoders[typ],
+		EncoderSource: b.sources.encoders[typ],
+		DecoratedBy:   b.sources.decorators[typ],
+	}
+	switch typ.Kind() {
+	case reflect.Ptr:
+		elemType := typ.(*reflect2.UnsafePtrType).Elem()
+		if !explained[elemType] {
+			explanation.Elem = b.append("[elem]").explain(elemType, explained)
+		}
+	case reflect.Struct:
+		if isStructDecoder(decoder) || isStructEncoder(encoder) {
+			explanation.Fields, explanation.Removed = b.explainFields(typ, explained)
+		}
+	}
+	return explanation
+}
+
+func (b *ctx) explainFields(typ reflect2.Type, explained map[reflect2.Type]bool) ([]*FieldExplanation, []string) {
+	structDescriptor := describeStruct(b, typ)
+	decoded := decodingBindings(b, structDescriptor)
+	encoded := encodingBindings(b, structDescriptor)
+	fields := make([]*FieldExplanation, 0, len(structDescriptor.Fields))
+	for _, binding := range structDescriptor.Fields {
+		owner, path := bindingOwner(typ, binding)
+		field := &FieldExplanation{
+			Field:       path,
+			Type:        binding.Field.Type().String(),
+			FromNames:   binding.FromNames,
+			ToNames:     binding.ToNames,
+			DecoratedBy: b.sources.decorators[binding.Field.Type()],
+			UpdatedBy:   b.sources.updatedBy[binding],
+		}
+		for _, fromName := range binding.FromNames {
+			if decoded[fromName] != binding {
+				field.Conflicts = appendNew(field.Conflicts, fromName)
+			}
+		}
+		for _, to := range encoded {
+			if to.binding == binding && to.ignored {
+				field.Conflicts = appendNew(field.Conflicts, to.toName)
+			}
+		}
+		field.Decoder, field.StringMode = fieldDecoderName(binding.Decoder)
+		field.Encoder, field.OmitEmpty = fieldEncoderName(binding.Encoder)
+		field.DecoderSource, field.EncoderSource = b.fieldSources(owner, binding.Field)
+		if elemType := structElemOf(binding.Field.Type()); elemType != nil && !explained[elemType] {
+			elemCtx := b.append(binding.Field.Name())
+			elemCtx.projection = structElemProjection(b.projection, binding)
+			elem := elemCtx.explain(elemType, explained)
+			if elem.Fields != nil {
+				field.Struct = elem
+			}
+		}
+		fields = append(fields, field)
+	}
+	return fields, b.sources.removed[typ]
+}
+
+// bindingOwner finds the struct declaring the field of binding, and the path to the field from typ
+func bindingOwner(typ reflect2.Type, binding *Binding) (reflect2.Type, string) {
+	path := []string{}
+	for _, level := range binding.levels[:len(binding.levels)-1] {
+		field := typ.(*reflect2.UnsafeStructType).Field(level)
+		path = append(path, field.Name())
+		typ = field.Type()
+		if typ.Kind() == reflect.Ptr {
+			typ = typ.(*reflect2.UnsafePtrType).Elem()
+		}
+	}
+	return typ, strings.Join(append(path, binding.Field.Name()), ".")
+}
+
+// fieldSources tells who supplied the codecs of field, declared by owner
+func (b *ctx) fieldSources(owner reflect2.Type, field reflect2.StructField) (decoderSource, encoderSource string) {
+	key := fieldCodecKey{owner.RType(), field.Name()}
+	fieldCacheKey := fmt.Sprintf("%s/%s", owner.String(), field.Name())
+	if b.fieldDecoders[key] != nil {
+		decoderSource = "RegisterFieldDecoderOf"
+	} else if fieldDecoders[fieldCacheKey] != nil {
+		decoderSource = "RegisterFieldDecoder"
+	} else {
+		decoderSource = b.sources.decoders[field.Type()]
+	}
+	if b.fieldEncoders[key] != nil {
+		encoderSource = "RegisterFieldEncoderOf"
+	} else if fieldEncoders[fieldCacheKey] != nil {
+		encoderSource = "RegisterFieldEncoder"
+	} else {
+		encoderSource = b.sources.encoders[field.Type()]
+	}
+	return
+}
+
+// structElemOf finds the struct held by a field of typ, through pointers, slices, arrays and map values
+func structElemOf(typ reflect2.Type) reflect2.Type {
+	// a type such as type T []T holds itself, but no struct
+	visited := map[reflect2.Type]bool{}
+	for !visited[typ] {
+		visited[typ] = true
+		switch typ.Kind() {
+		case reflect.Struct:
+			return typ
+		case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+			typ = reflect2.Type2(typ.Type1().Elem())
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// structElemProjection returns the projection of the struct held by the field of binding, nil for the whole struct
+func structElemProjection(projection *FieldMask, binding *Binding) *FieldMask {
+	if projection == nil {
+		return nil
+	}
+	for typ := binding.Field.Type(); typ.Kind() != reflect.Struct; typ = reflect2.Type2(typ.Type1().Elem()) {
+		if typ.Kind() == reflect.Map {
+			// projected by key, explained whole
+			return nil
+		}
+	}
+	return projection.childOf(binding.FromNames)
+}
+
+func isStructDecoder(decoder ValDecoder) bool {
+	switch decoder.(type) {
+	case *structDecoder, *skipObjectDecoder, *fieldSetDecoder:
+		return true
+	}
+	return false
+}
+
+func isStructEncoder(encoder ValEncoder) bool {
+	switch encoder.(type) {
+	case *structEncoder, *emptyStructEncoder:
+		return true
+	}
+	return false
+}
+
+// fieldDecoderName unwraps the binding decoder down to the decoder of the field value
+func fieldDecoderName(decoder ValDecoder) (name string, stringMode bool) {
+	for {
+		switch unwrapped := decoder.(type) {
+		case *structFieldDecoder:
+			decoder = unwrapped.fieldDecoder
+		case *placeholderDecoder:
+			decoder = unwrapped.decoder
+		case *dereferenceDecoder:
+			decoder = unwrapped.valueDecoder
+		case *stringModeStringDecoder:
+			decoder, stringMode = unwrapped.elemDecoder, true
+		case *stringModeNumberDecoder:
+			decoder, stringMode = unwrapped.elemDecoder, true
+		default:
+			return codecName(decoder), stringMode
+		}
+	}
+}
+
+// fieldEncoderName unwraps the binding encoder down to the encoder of the field value
+func fieldEncoderName(encoder ValEncoder) (name string, omitEmpty bool) {
+	if fieldEncoder, isFieldEncoder := encoder.(*structFieldEncoder); isFieldEncoder {
+		omitEmpty = fieldEncoder.omitempty
+	}
+	for {
+		switch unwrapped := encoder.(type) {
+		case *structFieldEncoder:
+			encoder = unwrapped.fieldEncoder
+		case *placeholderEncoder:
+			encoder = unwrapped.encoder
+		case *dereferenceEncoder:
+			encoder = unwrapped.ValueEncoder
+		case *stringModeStringEncoder:
+			encoder = unwrapped.elemEncoder
+		case *stringModeNumberEncoder:
+			encoder = unwrapped.elemEncoder
+		default:
+			return codecName(encoder), omitEmpty
+		}
+	}
+}
+
+func codecName(codec interface{}) string {
+	if codec == nil {
+		return ""
+	}
+	return fmt.Sprintf("%T", codec)
+}
+
+func appendNew(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+// String dumps the explanation as indented text
+func (explanation *Explanation) String() string {
+	var builder strings.Builder
+	explanation.writeTo(&builder, "")
+	return builder.String()
+}
+
+func (explanation *Explanation) writeTo(builder *strings.Builder, indent string) {
+	fmt.Fprintf(builder, "%s%s\n", indent, explanation.Type)
+	writeCodec(builder, indent+"  ", "decoder", explanation.Decoder, explanation.DecoderSource)
+	writeCodec(builder, indent+"  ", "encoder", explanation.Encoder, explanation.EncoderSource)
+	if len(explanation.DecoratedBy) > 0 {
+		fmt.Fprintf(builder, "%s  decorated by %s\n", indent, strings.Join(explanation.DecoratedBy, ", "))
+	}
+	if explanation.Elem != nil {
+		fmt.Fprintf(builder, "%s  elem:\n", indent)
+		explanation.Elem.writeTo(builder, indent+"    ")
+	}
+	for _, field := range explanation.Fields {
+		field.writeTo(builder, indent+"  ")
+	}
+	for _, removed := range explanation.Removed {
+		fmt.Fprintf(builder, "%s  removed %s\n", indent, removed)
+	}
+}
+
+func (field *FieldExplanation) writeTo(builder *strings.Builder, indent string) {
+	fmt.Fprintf(builder, "%sfield %s %s\n", indent, field.Field, field.Type)
+	options := []string{"from " + quoteNames(field.FromNames), "to " + quoteNames(field.ToNames)}
+	if len(field.Conflicts) > 0 {
+		options = append(options, "conflicts "+quoteNames(field.Conflicts))
+	}
+	if field.OmitEmpty {
+		options = append(options, "omitempty")
+	}
+	if field.StringMode {
+		options = append(options, "string")
+	}
+	fmt.Fprintf(builder, "%s  %s\n", indent, strings.Join(options, ", "))
+	writeCodec(builder, indent+"  ", "decoder", field.Decoder, field.DecoderSource)
+	writeCodec(builder, indent+"  ", "encoder", field.Encoder, field.EncoderSource)
+	if len(field.DecoratedBy) > 0 {
+		fmt.Fprintf(builder, "%s  decorated by %s\n", indent, strings.Join(field.DecoratedBy, ", "))
+	}
+	if len(field.UpdatedBy) > 0 {
+		fmt.Fprintf(builder, "%s  updated by %s\n", indent, strings.Join(field.UpdatedBy, ", "))
+	}
+	if field.Struct != nil {
+		field.Struct.writeTo(builder, indent+"  ")
+	}
+}
+
+func writeCodec(builder *strings.Builder, indent string, kind string, name string, source string) {
+	if source == "" {
+		fmt.Fprintf(builder, "%s%s %s\n", indent, kind, name)
+	} else {
+		fmt.Fprintf(builder, "%s%s %s from %s\n", indent, kind, name, source)
+	}
+}
+
+func quoteNames(names []string) string {
+	if len(names) == 0 {
+		return "none"
+	}
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = `"` + name + `"`
+	}
+	return strings.Join(quoted, " ")
+}
+
+// codecSources records who supplied and changed the codecs built, for Explain
+type codecSources struct {
+	decoders   map[reflect2.Type]string
+	encoders   map[reflect2.Type]string
+	decorators map[reflect2.Type][]string
+	updatedBy  map[*Binding][]string
+	removed    map[reflect2.Type][]string
+}
+
+func newCodecSources() *codecSources {
+	return &codecSources{
+		decoders:   map[reflect2.Type]string{},
+		encoders:   map[reflect2.Type]string{},
+		decorators: map[reflect2.Type][]string{},
+		updatedBy:  map[*Binding][]string{},
+		removed:    map[reflect2.Type][]string{},
+	}
+}
+
+func (sources *codecSources) decoderFrom(typ reflect2.Type, source string) {
+	if sources != nil {
+		sources.decoders[typ] = source
+	}
+}
+
+func (sources *codecSources) encoderFrom(typ reflect2.Type, source string) {
+	if sources != nil {
+		sources.encoders[typ] = source
+	}
+}
+
+func (sources *codecSources) decorated(typ reflect2.Type, extension Extension, before, after interface{}) {
+	if sources != nil && !sameCodec(before, after) {
+		sources.decorators[typ] = appendNew(sources.decorators[typ], extensionName(extension))
+	}
+}
+
+// updateStructDescriptor applies extension to structDescriptor, recording the bindings it changed or removed
+func (sources *codecSources) updateStructDescriptor(extension Extension, structDescriptor *StructDescriptor) {
+	if sources == nil {
+		extension.UpdateStructDescriptor(structDescriptor)
+		return
+	}
+	bindings := append([]*Binding(nil), structDescriptor.Fields...)
+	before := map[*Binding]Binding{}
+	for _, binding := range bindings {
+		before[binding] = *binding
+	}
+	extension.UpdateStructDescriptor(structDescriptor)
+	name := extensionName(extension)
+	for _, binding := range structDescriptor.Fields {
+		old, found := before[binding]
+		if !found || !reflect.DeepEqual(old.FromNames, binding.FromNames) || !reflect.DeepEqual(old.ToNames, binding.ToNames) ||
+			!sameCodec(old.Decoder, binding.Decoder) || !sameCodec(old.Encoder, binding.Encoder) {
+			sources.updatedBy[binding] = appendNew(sources.updatedBy[binding], name)
+		}
+		delete(before, binding)
+	}
+	for _, binding := range bindings {
+		if _, removed := before[binding]; removed {
+			sources.removed[structDescriptor.Type] = appendNew(sources.removed[structDescriptor.Type],
+				binding.Field.Name()+" by "+name)
+		}
+	}
+}
+
+func extensionName(extension Extension) string {
+	return fmt.Sprintf("%T", extension)
+}
+
+func sameCodec(left, right interface{}) bool {
+	if left == nil || right == nil {
+		return left == right
+	}
+	typ := reflect.TypeOf(left)
+	return typ == reflect.TypeOf(right) && typ.Comparable() && left == right
+}
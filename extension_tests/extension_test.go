@@ -1,6 +1,8 @@
 package test
 
 import (
+	"bytes"
+	"fmt"
 	"github.com/json-iterator/go"
 	"github.com/modern-go/reflect2"
 	"github.com/stretchr/testify/require"
@@ -118,3 +120,172 @@ func (encoder *funcEncoder) IsEmpty(ptr unsafe.Pointer) bool {
 	}
 	return encoder.isEmptyFunc(ptr)
 }
+
+func Test_register_codec_per_api(t *testing.T) {
+	should := require.New(t)
+	var userType1, userType2 reflect.Type
+	{
+		type User struct{ Name string }
+		userType1 = reflect.TypeOf(User{})
+	}
+	{
+		type User struct{ Name string }
+		userType2 = reflect.TypeOf(User{})
+	}
+	should.Equal(userType1.String(), userType2.String())
+	user1 := reflect.New(userType1).Interface()
+	user2 := reflect.New(userType2).Interface()
+	api := jsoniter.Config{}.Froze()
+	output, err := api.MarshalToString(user1)
+	should.NoError(err)
+	should.Equal(`{"Name":""}`, output)
+	jsoniter.RegisterEncoderOf(api, userType1, &funcEncoder{fun: func(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+		stream.WriteString("user1")
+	}})
+	jsoniter.RegisterDecoderOf(api, userType1, &funcDecoder{func(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+		iter.Skip()
+	}})
+	jsoniter.RegisterFieldEncoderOf(api, userType2, "Name", &funcEncoder{fun: func(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+		stream.WriteString("name2")
+	}})
+	for _, testCase := range []struct {
+		api    jsoniter.API
+		val    interface{}
+		output string
+	}{
+		{api, user1, `"user1"`},
+		{api, user2, `{"Name":"name2"}`},
+		{jsoniter.Config{}.Froze(), user1, `{"Name":""}`},
+		{jsoniter.ConfigDefault, user2, `{"Name":""}`},
+	} {
+		output, err := testCase.api.MarshalToString(testCase.val)
+		should.NoError(err)
+		should.Equal(testCase.output, output)
+	}
+	should.NoError(api.UnmarshalFromString(`{"Name":"x"}`, user1))
+	should.Equal(`{Name:}`, fmt.Sprintf("%+v", reflect.ValueOf(user1).Elem().Interface()))
+	should.NoError(jsoniter.ConfigDefault.UnmarshalFromString(`{"Name":"x"}`, user1))
+	should.Equal(`{Name:x}`, fmt.Sprintf("%+v", reflect.ValueOf(user1).Elem().Interface()))
+}
+
+func Test_register_codec_of_api_kept_by_derived_api(t *testing.T) {
+	should := require.New(t)
+	type Money int
+	type Order struct {
+		M Money
+		N string
+	}
+	api := jsoniter.Config{}.Froze()
+	jsoniter.RegisterEncoderOf(api, reflect.TypeOf(Money(0)), &funcEncoder{fun: func(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+		stream.WriteString(fmt.Sprintf("$%d", *(*Money)(ptr)))
+	}})
+	jsoniter.RegisterFieldDecoderOf(api, reflect.TypeOf(Order{}), "N", &funcDecoder{func(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+		*(*string)(ptr) = "n:" + iter.ReadString()
+	}})
+	order := Order{M: 5}
+	output, err := api.MarshalIndent(order, "", " ")
+	should.NoError(err)
+	should.Equal("{\n \"M\": \"$5\",\n \"N\": \"\"\n}", string(output))
+	output, err = jsoniter.WithGroups(api, "public").Marshal(order)
+	should.NoError(err)
+	should.Equal(`{"M":"$5","N":""}`, string(output))
+	var buf bytes.Buffer
+	encoder := api.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	should.NoError(encoder.Encode(order))
+	should.Equal("{\"M\":\"$5\",\"N\":\"\"}\n", buf.String())
+	var decoded Order
+	should.NoError(jsoniter.WithProjection(api, "N").UnmarshalFromString(`{"M":1,"N":"x"}`, &decoded))
+	should.Equal(Order{N: "n:x"}, decoded)
+	decoder := api.NewDecoder(bytes.NewBufferString(`{"N":"y"}`))
+	decoder.UseNumber()
+	should.NoError(decoder.Decode(&decoded))
+	should.Equal("n:y", decoded.N)
+
+	output, err = jsoniter.Config{}.Froze().MarshalIndent(order, "", " ")
+	should.NoError(err)
+	should.Equal("{\n \"M\": 5,\n \"N\": \"\"\n}", string(output))
+}
+
+type chainedPoint struct {
+	X, Y int
+}
+
+type countingExtension struct {
+	jsoniter.DummyExtension
+	decoded int
+}
+
+func (extension *countingExtension) ChainDecoder(typ reflect2.Type, next func() jsoniter.ValDecoder) jsoniter.ValDecoder {
+	if typ != reflect2.TypeOf(chainedPoint{}) {
+		return nil
+	}
+	decoder := next()
+	return &funcDecoder{func(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+		extension.decoded++
+		decoder.Decode(ptr, iter)
+		if point := (*chainedPoint)(ptr); point.X < 0 {
+			iter.ReportError("chainedPoint", "negative x")
+		}
+	}}
+}
+
+func (extension *countingExtension) ChainEncoder(typ reflect2.Type, next func() jsoniter.ValEncoder) jsoniter.ValEncoder {
+	if typ != reflect2.TypeOf(chainedPoint{}) {
+		return nil
+	}
+	encoder := next()
+	return &funcEncoder{fun: func(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+		stream.WriteArrayStart()
+		encoder.Encode(ptr, stream)
+		stream.WriteArrayEnd()
+	}}
+}
+
+type prioritizedExtension struct {
+	jsoniter.DummyExtension
+	priority int
+	output   string
+}
+
+func (extension *prioritizedExtension) Priority() int {
+	return extension.priority
+}
+
+func (extension *prioritizedExtension) CreateEncoder(typ reflect2.Type) jsoniter.ValEncoder {
+	if typ != reflect2.TypeOf(chainedPoint{}) {
+		return nil
+	}
+	return &funcEncoder{fun: func(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+		stream.WriteString(extension.output)
+	}}
+}
+
+func Test_extension_chain(t *testing.T) {
+	should := require.New(t)
+	api := jsoniter.Config{}.Froze()
+	counting := &countingExtension{}
+	api.RegisterExtension(counting)
+	var points []chainedPoint
+	should.NoError(api.UnmarshalFromString(`[{"X":1,"Y":2},{"X":3}]`, &points))
+	should.Equal([]chainedPoint{{1, 2}, {3, 0}}, points)
+	should.Equal(2, counting.decoded)
+	should.Error(api.UnmarshalFromString(`[{"X":-1}]`, &points))
+	output, err := api.MarshalToString(chainedPoint{1, 2})
+	should.NoError(err)
+	should.Equal(`[{"X":1,"Y":2}]`, output)
+
+	api = jsoniter.Config{}.Froze()
+	api.RegisterExtension(&prioritizedExtension{output: "low"})
+	api.RegisterExtension(counting)
+	api.RegisterExtension(&prioritizedExtension{priority: 1, output: "high"})
+	output, err = api.MarshalToString(chainedPoint{1, 2})
+	should.NoError(err)
+	should.Equal(`"high"`, output)
+	api = jsoniter.Config{}.Froze()
+	api.RegisterExtension(&prioritizedExtension{priority: -1, output: "low"})
+	api.RegisterExtension(counting)
+	output, err = api.MarshalToString(chainedPoint{1, 2})
+	should.NoError(err)
+	should.Equal(`["low"]`, output)
+}
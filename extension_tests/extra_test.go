@@ -0,0 +1,57 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/json-iterator/go"
+	"github.com/json-iterator/go/extra"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_extra_extensions_are_config_scoped(t *testing.T) {
+	should := require.New(t)
+	type TestObject struct {
+		UserID  int
+		Name    string
+		private int
+		Nested  struct{ Field int }
+		Created time.Time
+		Data    []byte
+	}
+	api := jsoniter.Config{}.Froze()
+	api.RegisterExtension(extra.FuzzyDecoders())
+	api.RegisterExtension(extra.PrivateFields())
+	api.RegisterExtension(extra.NamingStrategy(extra.SnakeCase))
+	api.RegisterExtension(extra.TimeAsInt64Codec(time.Second))
+	api.RegisterExtension(&extra.BinaryAsStringExtension{})
+	other := jsoniter.Config{}.Froze()
+
+	input := `{"user_id":"1","name":2,"private":3,"nested":[],"created":4,"data":"ab"}`
+	var obj TestObject
+	should.NoError(api.UnmarshalFromString(input, &obj))
+	should.Equal(1, obj.UserID)
+	should.Equal("2", obj.Name)
+	should.Equal(3, obj.private)
+	should.Equal(int64(4), obj.Created.Unix())
+	should.Equal("ab", string(obj.Data))
+	output, err := api.MarshalToString(obj)
+	should.NoError(err)
+	should.Equal(`{"user_id":1,"name":"2","private":3,"nested":{"field":0},"created":4,"data":"ab"}`, output)
+
+	for _, unaffected := range []jsoniter.API{other, jsoniter.ConfigDefault} {
+		var obj TestObject
+		should.Error(unaffected.UnmarshalFromString(`{"UserID":"1"}`, &obj))
+		should.Error(unaffected.UnmarshalFromString(`{"Name":2}`, &obj))
+		should.Error(unaffected.UnmarshalFromString(`{"Nested":[]}`, &obj))
+		should.NoError(unaffected.UnmarshalFromString(`{"userid":1,"private":3,"Data":"YWI="}`, &obj))
+		should.Equal(TestObject{UserID: 1, Data: []byte("ab")}, obj)
+		output, err := unaffected.MarshalToString(struct {
+			UserID  int
+			private int
+			Created time.Time
+		}{1, 2, time.Unix(0, 0).UTC()})
+		should.NoError(err)
+		should.Equal(`{"UserID":1,"Created":"1970-01-01T00:00:00Z"}`, output)
+	}
+}
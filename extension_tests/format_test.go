@@ -0,0 +1,133 @@
+package test
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unsafe"
+
+	"github.com/json-iterator/go"
+	"github.com/modern-go/reflect2"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_time_format(t *testing.T) {
+	should := require.New(t)
+	type TestObject struct {
+		Unix      time.Time  `json:"unix,format:unix"`
+		UnixMilli time.Time  `json:"unix_milli,format:unixmilli"`
+		Layout    time.Time  `json:"layout,format:2006-01-02"`
+		Ptr       *time.Time `json:"ptr,format:unix"`
+		Empty     time.Time  `json:"empty,omitempty,format:unix"`
+	}
+	ts := time.Date(2020, 1, 2, 3, 4, 5, 6000000, time.UTC)
+	output, err := jsoniter.MarshalToString(TestObject{Unix: ts, UnixMilli: ts, Layout: ts})
+	should.NoError(err)
+	should.Equal(`{"unix":1577934245,"unix_milli":1577934245006,"layout":"2020-01-02","ptr":null}`, output)
+	var obj TestObject
+	should.NoError(jsoniter.UnmarshalFromString(
+		`{"unix":1577934245,"unix_milli":1577934245006,"layout":"2020-01-02","ptr":1577934245}`, &obj))
+	should.True(ts.Truncate(time.Second).Equal(obj.Unix))
+	should.True(ts.Truncate(time.Millisecond).Equal(obj.UnixMilli))
+	should.Equal(time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), obj.Layout)
+	should.True(ts.Truncate(time.Second).Equal(*obj.Ptr))
+	should.Error(jsoniter.UnmarshalFromString(`{"layout":"01/02/2020"}`, &obj))
+}
+
+func Test_bytes_format(t *testing.T) {
+	should := require.New(t)
+	type TestObject struct {
+		Hex       []byte `json:"hex,format:hex"`
+		Base64URL []byte `json:"base64url,format:base64url"`
+	}
+	output, err := jsoniter.MarshalToString(TestObject{Hex: []byte{0xfb, 0xff}, Base64URL: []byte{0xfb, 0xff}})
+	should.NoError(err)
+	should.Equal(`{"hex":"fbff","base64url":"-_8="}`, output)
+	var obj TestObject
+	should.NoError(jsoniter.UnmarshalFromString(output, &obj))
+	should.Equal([]byte{0xfb, 0xff}, obj.Hex)
+	should.Equal([]byte{0xfb, 0xff}, obj.Base64URL)
+}
+
+func Test_float_precision(t *testing.T) {
+	should := require.New(t)
+	type TestObject struct {
+		F32 float32  `json:"f32,precision=2"`
+		F64 float64  `json:"f64,precision=3"`
+		Ptr *float64 `json:"ptr,precision=1"`
+	}
+	ptr := 0.25
+	output, err := jsoniter.MarshalToString(TestObject{F32: 1.005, F64: 2, Ptr: &ptr})
+	should.NoError(err)
+	should.Equal(`{"f32":1.00,"f64":2.000,"ptr":0.2}`, output)
+	var obj TestObject
+	should.NoError(jsoniter.UnmarshalFromString(`{"f32":1.5,"f64":2.25}`, &obj))
+	should.Equal(float32(1.5), obj.F32)
+	should.Equal(2.25, obj.F64)
+}
+
+func Test_duration_format(t *testing.T) {
+	should := require.New(t)
+	type TestObject struct {
+		Timeout time.Duration `json:"timeout,format:units"`
+	}
+	output, err := jsoniter.MarshalToString(TestObject{Timeout: 90 * time.Second})
+	should.NoError(err)
+	should.Equal(`{"timeout":"1m30s"}`, output)
+	var obj TestObject
+	should.NoError(jsoniter.UnmarshalFromString(`{"timeout":"1h"}`, &obj))
+	should.Equal(time.Hour, obj.Timeout)
+	should.NoError(jsoniter.UnmarshalFromString(`{"timeout":1000}`, &obj))
+	should.Equal(time.Microsecond, obj.Timeout)
+}
+
+func Test_invalid_format(t *testing.T) {
+	should := require.New(t)
+	_, err := jsoniter.Marshal(struct {
+		Field int `json:"field,format:unix"`
+	}{})
+	should.Error(err)
+	_, err = jsoniter.Marshal(struct {
+		Field string `json:"field,format:no-such-format"`
+	}{})
+	should.Error(err)
+	_, err = jsoniter.Marshal(struct {
+		Field time.Time `json:"field,format:Mon,02-Jan-2006,omitempty"`
+	}{})
+	should.Error(err)
+	should.Contains(err.Error(), "can not contain a comma")
+	output, err := jsoniter.Marshal(struct {
+		Field time.Time `json:"field,format:02-Jan-2006,omitempty,alias=f"`
+	}{Field: time.Date(2006, 1, 2, 0, 0, 0, 0, time.UTC)})
+	should.NoError(err)
+	should.Equal(`{"field":"02-Jan-2006"}`, string(output))
+}
+
+type upperFormat struct {
+}
+
+func (format *upperFormat) DecorateDecoder(typ reflect2.Type, decoder jsoniter.ValDecoder) jsoniter.ValDecoder {
+	if typ.Kind() != reflect2.TypeOf("").Kind() {
+		return nil
+	}
+	return decoder
+}
+
+func (format *upperFormat) DecorateEncoder(typ reflect2.Type, encoder jsoniter.ValEncoder) jsoniter.ValEncoder {
+	if typ.Kind() != reflect2.TypeOf("").Kind() {
+		return nil
+	}
+	return &funcEncoder{fun: func(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+		stream.WriteString(strings.ToUpper(*((*string)(ptr))))
+	}}
+}
+
+func Test_register_field_format(t *testing.T) {
+	should := require.New(t)
+	jsoniter.RegisterFieldFormat("upper", &upperFormat{})
+	output, err := jsoniter.MarshalToString(struct {
+		Field string `json:"field,format:upper"`
+	}{"hello"})
+	should.NoError(err)
+	should.Equal(`{"field":"HELLO"}`, output)
+}
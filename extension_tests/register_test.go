@@ -0,0 +1,49 @@
+package test
+
+import (
+	"strings"
+	"testing"
+	"unsafe"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+type upperString string
+
+func Test_register_codec_generic(t *testing.T) {
+	should := require.New(t)
+	api := jsoniter.Config{}.Froze()
+	jsoniter.RegisterDecoderFunc(api, func(val *upperString, iter *jsoniter.Iterator) {
+		*val = upperString(strings.ToUpper(iter.ReadString()))
+	})
+	jsoniter.RegisterEncoderFunc(api, func(val *upperString, stream *jsoniter.Stream) {
+		stream.WriteString(strings.ToLower(string(*val)))
+	}, func(val *upperString) bool {
+		return *val == ""
+	})
+	var val struct {
+		Field upperString `json:",omitempty"`
+		Ptr   *upperString
+	}
+	should.NoError(api.UnmarshalFromString(`{"Field":"abc","Ptr":"def"}`, &val))
+	should.Equal(upperString("ABC"), val.Field)
+	should.Equal(upperString("DEF"), *val.Ptr)
+	output, err := api.MarshalToString(val)
+	should.NoError(err)
+	should.Equal(`{"Field":"abc","Ptr":"def"}`, output)
+	val.Field = ""
+	output, err = api.MarshalToString(val)
+	should.NoError(err)
+	should.Equal(`{"Ptr":"def"}`, output)
+	output, err = jsoniter.ConfigDefault.MarshalToString(upperString("ABC"))
+	should.NoError(err)
+	should.Equal(`"ABC"`, output)
+
+	jsoniter.RegisterCodec[upperString](api, nil, &funcEncoder{fun: func(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+		stream.WriteRaw(`"codec"`)
+	}})
+	output, err = api.MarshalToString(upperString("ABC"))
+	should.NoError(err)
+	should.Equal(`"codec"`, output)
+}
@@ -115,6 +115,8 @@ var safeSet = [utf8.RuneSelf]bool{
 
 var binaryType = reflect2.TypeOfPtr((*[]byte)(nil)).Elem()
 
+// BinaryAsStringExtension encodes []byte as a string, escaping the bytes out of printable ASCII as \x00.
+// Register it with jsoniter.RegisterExtension, or on a single config with API.RegisterExtension.
 type BinaryAsStringExtension struct {
 	jsoniter.DummyExtension
 }
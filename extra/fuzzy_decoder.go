@@ -20,129 +20,151 @@ const minInt = -maxInt - 1
 // It will handle string/number auto conversation, and treat empty [] as empty struct.
 func RegisterFuzzyDecoders() {
 	jsoniter.RegisterExtension(&tolerateEmptyArrayExtension{})
-	jsoniter.RegisterTypeDecoder("string", &fuzzyStringDecoder{})
-	jsoniter.RegisterTypeDecoder("float32", &fuzzyFloat32Decoder{})
-	jsoniter.RegisterTypeDecoder("float64", &fuzzyFloat64Decoder{})
-	jsoniter.RegisterTypeDecoder("int", &fuzzyIntegerDecoder{func(isFloat bool, ptr unsafe.Pointer, iter *jsoniter.Iterator) {
-		if isFloat {
-			val := iter.ReadFloat64()
-			if val > float64(maxInt) || val < float64(minInt) {
-				iter.ReportError("fuzzy decode int", "exceed range")
-				return
+	for typ, decoder := range createFuzzyDecoders() {
+		jsoniter.RegisterTypeDecoder(typ.String(), decoder)
+	}
+}
+
+// FuzzyDecoders returns the extension doing RegisterFuzzyDecoders, to register on a single config.
+func FuzzyDecoders() jsoniter.Extension {
+	return &fuzzyDecodersExtension{decoders: createFuzzyDecoders()}
+}
+
+type fuzzyDecodersExtension struct {
+	tolerateEmptyArrayExtension
+	decoders jsoniter.DecoderExtension
+}
+
+func (extension *fuzzyDecodersExtension) CreateDecoder(typ reflect2.Type) jsoniter.ValDecoder {
+	return extension.decoders.CreateDecoder(typ)
+}
+
+func createFuzzyDecoders() jsoniter.DecoderExtension {
+	return jsoniter.DecoderExtension{
+		reflect2.TypeOf(""):         &fuzzyStringDecoder{},
+		reflect2.TypeOf(float32(0)): &fuzzyFloat32Decoder{},
+		reflect2.TypeOf(float64(0)): &fuzzyFloat64Decoder{},
+		reflect2.TypeOf(int(0)): &fuzzyIntegerDecoder{func(isFloat bool, ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+			if isFloat {
+				val := iter.ReadFloat64()
+				if val > float64(maxInt) || val < float64(minInt) {
+					iter.ReportError("fuzzy decode int", "exceed range")
+					return
+				}
+				*((*int)(ptr)) = int(val)
+			} else {
+				*((*int)(ptr)) = iter.ReadInt()
 			}
-			*((*int)(ptr)) = int(val)
-		} else {
-			*((*int)(ptr)) = iter.ReadInt()
-		}
-	}})
-	jsoniter.RegisterTypeDecoder("uint", &fuzzyIntegerDecoder{func(isFloat bool, ptr unsafe.Pointer, iter *jsoniter.Iterator) {
-		if isFloat {
-			val := iter.ReadFloat64()
-			if val > float64(maxUint) || val < 0 {
-				iter.ReportError("fuzzy decode uint", "exceed range")
-				return
+		}},
+		reflect2.TypeOf(uint(0)): &fuzzyIntegerDecoder{func(isFloat bool, ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+			if isFloat {
+				val := iter.ReadFloat64()
+				if val > float64(maxUint) || val < 0 {
+					iter.ReportError("fuzzy decode uint", "exceed range")
+					return
+				}
+				*((*uint)(ptr)) = uint(val)
+			} else {
+				*((*uint)(ptr)) = iter.ReadUint()
 			}
-			*((*uint)(ptr)) = uint(val)
-		} else {
-			*((*uint)(ptr)) = iter.ReadUint()
-		}
-	}})
-	jsoniter.RegisterTypeDecoder("int8", &fuzzyIntegerDecoder{func(isFloat bool, ptr unsafe.Pointer, iter *jsoniter.Iterator) {
-		if isFloat {
-			val := iter.ReadFloat64()
-			if val > float64(math.MaxInt8) || val < float64(math.MinInt8) {
-				iter.ReportError("fuzzy decode int8", "exceed range")
-				return
+		}},
+		reflect2.TypeOf(int8(0)): &fuzzyIntegerDecoder{func(isFloat bool, ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+			if isFloat {
+				val := iter.ReadFloat64()
+				if val > float64(math.MaxInt8) || val < float64(math.MinInt8) {
+					iter.ReportError("fuzzy decode int8", "exceed range")
+					return
+				}
+				*((*int8)(ptr)) = int8(val)
+			} else {
+				*((*int8)(ptr)) = iter.ReadInt8()
 			}
-			*((*int8)(ptr)) = int8(val)
-		} else {
-			*((*int8)(ptr)) = iter.ReadInt8()
-		}
-	}})
-	jsoniter.RegisterTypeDecoder("uint8", &fuzzyIntegerDecoder{func(isFloat bool, ptr unsafe.Pointer, iter *jsoniter.Iterator) {
-		if isFloat {
-			val := iter.ReadFloat64()
-			if val > float64(math.MaxUint8) || val < 0 {
-				iter.ReportError("fuzzy decode uint8", "exceed range")
-				return
+		}},
+		reflect2.TypeOf(uint8(0)): &fuzzyIntegerDecoder{func(isFloat bool, ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+			if isFloat {
+				val := iter.ReadFloat64()
+				if val > float64(math.MaxUint8) || val < 0 {
+					iter.ReportError("fuzzy decode uint8", "exceed range")
+					return
+				}
+				*((*uint8)(ptr)) = uint8(val)
+			} else {
+				*((*uint8)(ptr)) = iter.ReadUint8()
 			}
-			*((*uint8)(ptr)) = uint8(val)
-		} else {
-			*((*uint8)(ptr)) = iter.ReadUint8()
-		}
-	}})
-	jsoniter.RegisterTypeDecoder("int16", &fuzzyIntegerDecoder{func(isFloat bool, ptr unsafe.Pointer, iter *jsoniter.Iterator) {
-		if isFloat {
-			val := iter.ReadFloat64()
-			if val > float64(math.MaxInt16) || val < float64(math.MinInt16) {
-				iter.ReportError("fuzzy decode int16", "exceed range")
-				return
+		}},
+		reflect2.TypeOf(int16(0)): &fuzzyIntegerDecoder{func(isFloat bool, ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+			if isFloat {
+				val := iter.ReadFloat64()
+				if val > float64(math.MaxInt16) || val < float64(math.MinInt16) {
+					iter.ReportError("fuzzy decode int16", "exceed range")
+					return
+				}
+				*((*int16)(ptr)) = int16(val)
+			} else {
+				*((*int16)(ptr)) = iter.ReadInt16()
 			}
-			*((*int16)(ptr)) = int16(val)
-		} else {
-			*((*int16)(ptr)) = iter.ReadInt16()
-		}
-	}})
-	jsoniter.RegisterTypeDecoder("uint16", &fuzzyIntegerDecoder{func(isFloat bool, ptr unsafe.Pointer, iter *jsoniter.Iterator) {
-		if isFloat {
-			val := iter.ReadFloat64()
-			if val > float64(math.MaxUint16) || val < 0 {
-				iter.ReportError("fuzzy decode uint16", "exceed range")
-				return
+		}},
+		reflect2.TypeOf(uint16(0)): &fuzzyIntegerDecoder{func(isFloat bool, ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+			if isFloat {
+				val := iter.ReadFloat64()
+				if val > float64(math.MaxUint16) || val < 0 {
+					iter.ReportError("fuzzy decode uint16", "exceed range")
+					return
+				}
+				*((*uint16)(ptr)) = uint16(val)
+			} else {
+				*((*uint16)(ptr)) = iter.ReadUint16()
 			}
-			*((*uint16)(ptr)) = uint16(val)
-		} else {
-			*((*uint16)(ptr)) = iter.ReadUint16()
-		}
-	}})
-	jsoniter.RegisterTypeDecoder("int32", &fuzzyIntegerDecoder{func(isFloat bool, ptr unsafe.Pointer, iter *jsoniter.Iterator) {
-		if isFloat {
-			val := iter.ReadFloat64()
-			if val > float64(math.MaxInt32) || val < float64(math.MinInt32) {
-				iter.ReportError("fuzzy decode int32", "exceed range")
-				return
+		}},
+		reflect2.TypeOf(int32(0)): &fuzzyIntegerDecoder{func(isFloat bool, ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+			if isFloat {
+				val := iter.ReadFloat64()
+				if val > float64(math.MaxInt32) || val < float64(math.MinInt32) {
+					iter.ReportError("fuzzy decode int32", "exceed range")
+					return
+				}
+				*((*int32)(ptr)) = int32(val)
+			} else {
+				*((*int32)(ptr)) = iter.ReadInt32()
 			}
-			*((*int32)(ptr)) = int32(val)
-		} else {
-			*((*int32)(ptr)) = iter.ReadInt32()
-		}
-	}})
-	jsoniter.RegisterTypeDecoder("uint32", &fuzzyIntegerDecoder{func(isFloat bool, ptr unsafe.Pointer, iter *jsoniter.Iterator) {
-		if isFloat {
-			val := iter.ReadFloat64()
-			if val > float64(math.MaxUint32) || val < 0 {
-				iter.ReportError("fuzzy decode uint32", "exceed range")
-				return
+		}},
+		reflect2.TypeOf(uint32(0)): &fuzzyIntegerDecoder{func(isFloat bool, ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+			if isFloat {
+				val := iter.ReadFloat64()
+				if val > float64(math.MaxUint32) || val < 0 {
+					iter.ReportError("fuzzy decode uint32", "exceed range")
+					return
+				}
+				*((*uint32)(ptr)) = uint32(val)
+			} else {
+				*((*uint32)(ptr)) = iter.ReadUint32()
 			}
-			*((*uint32)(ptr)) = uint32(val)
-		} else {
-			*((*uint32)(ptr)) = iter.ReadUint32()
-		}
-	}})
-	jsoniter.RegisterTypeDecoder("int64", &fuzzyIntegerDecoder{func(isFloat bool, ptr unsafe.Pointer, iter *jsoniter.Iterator) {
-		if isFloat {
-			val := iter.ReadFloat64()
-			if val > float64(math.MaxInt64) || val < float64(math.MinInt64) {
-				iter.ReportError("fuzzy decode int64", "exceed range")
-				return
+		}},
+		reflect2.TypeOf(int64(0)): &fuzzyIntegerDecoder{func(isFloat bool, ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+			if isFloat {
+				val := iter.ReadFloat64()
+				if val > float64(math.MaxInt64) || val < float64(math.MinInt64) {
+					iter.ReportError("fuzzy decode int64", "exceed range")
+					return
+				}
+				*((*int64)(ptr)) = int64(val)
+			} else {
+				*((*int64)(ptr)) = iter.ReadInt64()
 			}
-			*((*int64)(ptr)) = int64(val)
-		} else {
-			*((*int64)(ptr)) = iter.ReadInt64()
-		}
-	}})
-	jsoniter.RegisterTypeDecoder("uint64", &fuzzyIntegerDecoder{func(isFloat bool, ptr unsafe.Pointer, iter *jsoniter.Iterator) {
-		if isFloat {
-			val := iter.ReadFloat64()
-			if val > float64(math.MaxUint64) || val < 0 {
-				iter.ReportError("fuzzy decode uint64", "exceed range")
-				return
+		}},
+		reflect2.TypeOf(uint64(0)): &fuzzyIntegerDecoder{func(isFloat bool, ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+			if isFloat {
+				val := iter.ReadFloat64()
+				if val > float64(math.MaxUint64) || val < 0 {
+					iter.ReportError("fuzzy decode uint64", "exceed range")
+					return
+				}
+				*((*uint64)(ptr)) = uint64(val)
+			} else {
+				*((*uint64)(ptr)) = iter.ReadUint64()
 			}
-			*((*uint64)(ptr)) = uint64(val)
-		} else {
-			*((*uint64)(ptr)) = iter.ReadUint64()
-		}
-	}})
+		}},
+	}
 }
 
 type tolerateEmptyArrayExtension struct {
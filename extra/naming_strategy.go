@@ -8,7 +8,13 @@ import (
 
 // SetNamingStrategy rename struct fields uniformly
 func SetNamingStrategy(translate func(string) string) {
-	jsoniter.RegisterExtension(&namingStrategyExtension{jsoniter.DummyExtension{}, translate})
+	jsoniter.RegisterExtension(NamingStrategy(translate))
+}
+
+// NamingStrategy returns the extension renaming struct fields uniformly, to register on a single config.
+// Fields named by tag keep their name.
+func NamingStrategy(translate func(string) string) jsoniter.Extension {
+	return &namingStrategyExtension{jsoniter.DummyExtension{}, translate}
 }
 
 type namingStrategyExtension struct {
@@ -53,3 +59,74 @@ func LowerCaseWithUnderscores(name string) string {
 	}
 	return string(newName)
 }
+
+// SnakeCase one strategy to NamingStrategy for. It will change HTTPServerID to http_server_id.
+func SnakeCase(name string) string {
+	return joinWords(splitWords(name), "_", strings.ToLower)
+}
+
+// ScreamingSnakeCase one strategy to NamingStrategy for. It will change HTTPServerID to HTTP_SERVER_ID.
+func ScreamingSnakeCase(name string) string {
+	return joinWords(splitWords(name), "_", strings.ToUpper)
+}
+
+// KebabCase one strategy to NamingStrategy for. It will change HTTPServerID to http-server-id.
+func KebabCase(name string) string {
+	return joinWords(splitWords(name), "-", strings.ToLower)
+}
+
+// PascalCase one strategy to NamingStrategy for. It will change HTTPServerID to HttpServerId.
+func PascalCase(name string) string {
+	return joinWords(splitWords(name), "", capitalize)
+}
+
+// CamelCase one strategy to NamingStrategy for. It will change HTTPServerID to httpServerId.
+func CamelCase(name string) string {
+	words := splitWords(name)
+	if len(words) == 0 {
+		return ""
+	}
+	return strings.ToLower(words[0]) + joinWords(words[1:], "", capitalize)
+}
+
+func capitalize(word string) string {
+	runes := []rune(strings.ToLower(word))
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+func joinWords(words []string, separator string, convert func(string) string) string {
+	converted := make([]string, len(words))
+	for i, word := range words {
+		converted[i] = convert(word)
+	}
+	return strings.Join(converted, separator)
+}
+
+// splitWords splits the name on '_' and '-', and before upper case letters.
+// A run of upper case letters is one word, an acronym, except its last letter starting a lower case word.
+// Digits stay with the word before them.
+func splitWords(name string) []string {
+	words := []string{}
+	runes := []rune(name)
+	start := 0
+	for i := 0; i <= len(runes); i++ {
+		if i == len(runes) || runes[i] == '_' || runes[i] == '-' {
+			if i > start {
+				words = append(words, string(runes[start:i]))
+			}
+			start = i + 1
+			continue
+		}
+		if i == start || !unicode.IsUpper(runes[i]) {
+			continue
+		}
+		prev := runes[i-1]
+		nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+		if unicode.IsLower(prev) || unicode.IsDigit(prev) || unicode.IsUpper(prev) && nextIsLower {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	return words
+}
@@ -64,3 +64,42 @@ func Test_set_naming_strategy_with_private_field(t *testing.T) {
 	should.Nil(err)
 	should.Equal(`{"user_name":"allen"}`, string(output))
 }
+
+func Test_naming_strategy_converters(t *testing.T) {
+	should := require.New(t)
+	for name, expected := range map[string][5]string{
+		"HTTPServer":    {"http_server", "HTTP_SERVER", "http-server", "HttpServer", "httpServer"},
+		"UserID":        {"user_id", "USER_ID", "user-id", "UserId", "userId"},
+		"userName":      {"user_name", "USER_NAME", "user-name", "UserName", "userName"},
+		"OAuth2Token":   {"o_auth2_token", "O_AUTH2_TOKEN", "o-auth2-token", "OAuth2Token", "oAuth2Token"},
+		"already_Snake": {"already_snake", "ALREADY_SNAKE", "already-snake", "AlreadySnake", "alreadySnake"},
+		"A":             {"a", "A", "a", "A", "a"},
+	} {
+		should.Equal(expected[0], SnakeCase(name), name)
+		should.Equal(expected[1], ScreamingSnakeCase(name), name)
+		should.Equal(expected[2], KebabCase(name), name)
+		should.Equal(expected[3], PascalCase(name), name)
+		should.Equal(expected[4], CamelCase(name), name)
+	}
+}
+
+func Test_naming_strategy_per_config(t *testing.T) {
+	should := require.New(t)
+	api := jsoniter.Config{}.Froze()
+	api.RegisterExtension(NamingStrategy(KebabCase))
+	type TestObject struct {
+		HTTPServer string
+		UserID     int `json:"uid"`
+	}
+	output, err := api.MarshalToString(TestObject{"a", 1})
+	should.NoError(err)
+	should.Equal(`{"http-server":"a","uid":1}`, output)
+	var decoded TestObject
+	should.NoError(api.UnmarshalFromString(output, &decoded))
+	should.Equal(TestObject{"a", 1}, decoded)
+	api = jsoniter.Config{}.Froze()
+	api.RegisterExtension(NamingStrategy(SnakeCase))
+	output, err = api.MarshalToString(TestObject{"a", 1})
+	should.NoError(err)
+	should.Equal(`{"http_server":"a","uid":1}`, output)
+}
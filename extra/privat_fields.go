@@ -8,7 +8,12 @@ import (
 
 // SupportPrivateFields include private fields when encoding/decoding
 func SupportPrivateFields() {
-	jsoniter.RegisterExtension(&privateFieldsExtension{})
+	jsoniter.RegisterExtension(PrivateFields())
+}
+
+// PrivateFields returns the extension doing SupportPrivateFields, to register on a single config.
+func PrivateFields() jsoniter.Extension {
+	return &privateFieldsExtension{}
 }
 
 type privateFieldsExtension struct {
@@ -2,6 +2,7 @@ package extra
 
 import (
 	"github.com/json-iterator/go"
+	"github.com/modern-go/reflect2"
 	"time"
 	"unsafe"
 )
@@ -12,6 +13,32 @@ func RegisterTimeAsInt64Codec(precision time.Duration) {
 	jsoniter.RegisterTypeDecoder("time.Time", &timeAsInt64Codec{precision})
 }
 
+// TimeAsInt64Codec returns the extension doing RegisterTimeAsInt64Codec, to register on a single config.
+func TimeAsInt64Codec(precision time.Duration) jsoniter.Extension {
+	return &timeAsInt64Extension{codec: &timeAsInt64Codec{precision}}
+}
+
+var timeType = reflect2.TypeOf(time.Time{})
+
+type timeAsInt64Extension struct {
+	jsoniter.DummyExtension
+	codec *timeAsInt64Codec
+}
+
+func (extension *timeAsInt64Extension) CreateDecoder(typ reflect2.Type) jsoniter.ValDecoder {
+	if typ == timeType {
+		return extension.codec
+	}
+	return nil
+}
+
+func (extension *timeAsInt64Extension) CreateEncoder(typ reflect2.Type) jsoniter.ValEncoder {
+	if typ == timeType {
+		return extension.codec
+	}
+	return nil
+}
+
 type timeAsInt64Codec struct {
 	precision time.Duration
 }
@@ -374,3 +374,179 @@ func Test_unmarshal_anonymous_struct_invalid(t *testing.T) {
 	should.NotContains(err.Error(), reflect.TypeOf(t10.Field10).String())
 	should.Contains(err.Error(), reflect.TypeOf(t10).String())
 }
+
+func Test_field_alias(t *testing.T) {
+	should := require.New(t)
+	type TestObject struct {
+		UserID int    `json:"user_id,alias=userId,alias=uid"`
+		Name   string `json:"name,omitempty,alias=username"`
+	}
+	for _, input := range []string{
+		`{"user_id":1,"name":"a"}`,
+		`{"userId":1,"username":"a"}`,
+		`{"uid":1,"name":"a"}`,
+		`{"UID":1,"Username":"a"}`,
+	} {
+		var obj TestObject
+		should.NoError(jsoniter.UnmarshalFromString(input, &obj), input)
+		should.Equal(TestObject{1, "a"}, obj, input)
+	}
+	output, err := jsoniter.MarshalToString(TestObject{1, "a"})
+	should.NoError(err)
+	should.Equal(`{"user_id":1,"name":"a"}`, output)
+	type ManyFields struct {
+		F1  int `json:"f1,alias=a1"`
+		F2  int `json:"f2,alias=a2"`
+		F3  int `json:"f3,alias=a3"`
+		F4  int `json:"f4,alias=a4"`
+		F5  int `json:"f5,alias=a5"`
+		F6  int `json:"f6,alias=a6"`
+		F7  int `json:"f7,alias=a7"`
+		F8  int `json:"f8,alias=a8"`
+		F9  int `json:"f9,alias=a9"`
+		F10 int `json:"f10,alias=a10"`
+		F11 int `json:"f11,alias=a11"`
+	}
+	var obj ManyFields
+	should.NoError(jsoniter.UnmarshalFromString(`{"a1":1,"f2":2,"a11":11}`, &obj))
+	should.Equal(ManyFields{F1: 1, F2: 2, F11: 11}, obj)
+	var strict TestObject
+	api := jsoniter.Config{DisallowUnknownFields: true, CaseSensitive: true}.Froze()
+	should.NoError(api.UnmarshalFromString(`{"uid":1,"username":"a"}`, &strict))
+	should.Equal(TestObject{1, "a"}, strict)
+}
+
+func Test_read_only_and_write_only_fields(t *testing.T) {
+	should := require.New(t)
+	type TestObject struct {
+		ID       int    `json:"id,readonly"`
+		Name     string `json:"name"`
+		Password string `json:"password,writeonly,alias=pass"`
+	}
+	obj := TestObject{ID: 1}
+	should.NoError(jsoniter.UnmarshalFromString(`{"id":2,"name":"a","pass":"secret"}`, &obj))
+	should.Equal(TestObject{ID: 1, Name: "a", Password: "secret"}, obj)
+	output, err := jsoniter.MarshalToString(obj)
+	should.NoError(err)
+	should.Equal(`{"id":1,"name":"a"}`, output)
+	api := jsoniter.Config{DisallowReadOnlyFields: true}.Froze()
+	err = api.UnmarshalFromString(`{"name":"b","id":2}`, &obj)
+	should.Error(err)
+	should.Contains(err.Error(), "ID: readOnlyFieldDecoder: field is read-only")
+	should.NoError(api.UnmarshalFromString(`{"name":"b"}`, &obj))
+}
+
+func Test_field_set(t *testing.T) {
+	should := require.New(t)
+	type Embedded struct {
+		Inner int `json:"inner"`
+	}
+	type Small struct {
+		Name    string `json:"name"`
+		Present jsoniter.FieldSet
+	}
+	type Large struct {
+		Embedded
+		F1, F2, F3, F4, F5, F6, F7, F8, F9, F10, F11 int
+		Present                                      jsoniter.FieldSet
+	}
+	var small Small
+	should.NoError(jsoniter.UnmarshalFromString(`{"name":"a","other":1}`, &small))
+	should.True(small.Present.Has("Name"))
+	should.NoError(jsoniter.UnmarshalFromString(`{}`, &small))
+	should.Equal([]string{}, small.Present.Names())
+	should.NoError(jsoniter.UnmarshalFromString(`null`, &small))
+	should.Equal([]string{}, small.Present.Names())
+	output, err := jsoniter.MarshalToString(small)
+	should.NoError(err)
+	should.Equal(`{"name":"a"}`, output)
+	for _, api := range []jsoniter.API{
+		jsoniter.ConfigDefault,
+		jsoniter.Config{DisallowUnknownFields: true}.Froze(),
+		jsoniter.Config{CaseSensitive: true}.Froze(),
+	} {
+		var large Large
+		should.NoError(api.UnmarshalFromString(`{"F2":0,"inner":1,"F11":null}`, &large))
+		should.Equal([]string{"F11", "F2", "Inner"}, large.Present.Names())
+	}
+}
+
+func Test_field_dispatch(t *testing.T) {
+	should := require.New(t)
+	type Large struct {
+		F1, F2, F3, F4, F5, F6, F7, F8, F9, F10, F11 int
+		Upper                                        int `json:"Name"`
+		Lower                                        int `json:"name"`
+		Unicode                                      int `json:"Été"`
+	}
+	var obj Large
+	should.NoError(jsoniter.UnmarshalFromString(
+		`{"f1":1,"F11":11,"F2":2,"Name":3,"NAME":4,"ÉTÉ":5,"F12":12,"F1x":0,"":0}`, &obj))
+	should.Equal(Large{F1: 1, F2: 2, F11: 11, Upper: 3, Lower: 4, Unicode: 5}, obj)
+	obj = Large{}
+	should.NoError(jsoniter.Config{CaseSensitive: true}.Froze().UnmarshalFromString(
+		`{"f1":1,"F11":11,"name":4,"ÉTÉ":5,"Été":6}`, &obj))
+	should.Equal(Large{F11: 11, Lower: 4, Unicode: 6}, obj)
+	should.Error(jsoniter.Config{DisallowUnknownFields: true}.Froze().UnmarshalFromString(`{"F12":1}`, &obj))
+	type Small struct {
+		Field int
+	}
+	var small Small
+	should.NoError(jsoniter.UnmarshalFromString(`{"Fielc":1,"Fiel":2,"field":3}`, &small))
+	should.Equal(Small{3}, small)
+}
+
+func Test_field_dispatch_no_allocation(t *testing.T) {
+	should := require.New(t)
+	type Large struct {
+		F1, F2, F3, F4, F5, F6, F7, F8, F9, F10, F11 int
+	}
+	input := []byte(`{"F1":1,"f5":5,"F11":11,"unknown":[1,{"a":2}]}`)
+	var obj Large
+	iter := jsoniter.ConfigDefault.BorrowIterator(input)
+	defer jsoniter.ConfigDefault.ReturnIterator(iter)
+	iter.ReadVal(&obj)
+	allocs := testing.AllocsPerRun(100, func() {
+		iter.ResetBytes(input)
+		iter.ReadVal(&obj)
+	})
+	should.Equal(float64(0), allocs)
+	should.Equal(Large{F1: 1, F5: 5, F11: 11}, obj)
+}
+
+func Test_field_matching(t *testing.T) {
+	type User struct {
+		UserID   int
+		FullName string `json:"full_name"`
+		Kelvin   int    `json:"k"`
+	}
+	testCases := []struct {
+		matching jsoniter.FieldMatching
+		input    string
+		output   User
+	}{
+		{jsoniter.FieldMatchExact, `{"userid":1,"UserID":2,"FULL_NAME":"a","\u212a":3}`, User{UserID: 2}},
+		{jsoniter.FieldMatchASCIIFold, `{"userid":1,"FULL_NAME":"a","\u212a":3}`, User{UserID: 1, FullName: "a"}},
+		{jsoniter.FieldMatchUnicodeFold, `{"userid":1,"FULL_NAME":"a","\u212a":3}`, User{UserID: 1, FullName: "a", Kelvin: 3}},
+		{jsoniter.FieldMatchUnicodeFold, `{"user_id":1,"fullname":"a"}`, User{}},
+		{jsoniter.FieldMatchIgnoreSeparators, `{"user_id":1,"FullName":"a","-k-":3}`, User{UserID: 1, FullName: "a", Kelvin: 3}},
+		{jsoniter.FieldMatchIgnoreSeparators, `{"user-Id":1,"fullname":"a"}`, User{UserID: 1, FullName: "a"}},
+	}
+	for _, testCase := range testCases {
+		should := require.New(t)
+		var user User
+		api := jsoniter.Config{FieldMatching: testCase.matching}.Froze()
+		should.NoError(api.UnmarshalFromString(testCase.input, &user))
+		should.Equal(testCase.output, user, testCase.input)
+	}
+	should := require.New(t)
+	type Conflict struct {
+		UserID  int `json:"UserID"`
+		UserId  int `json:"userid"`
+		User_ID int `json:"user_id"`
+	}
+	var conflict Conflict
+	api := jsoniter.Config{FieldMatching: jsoniter.FieldMatchIgnoreSeparators}.Froze()
+	should.NoError(api.UnmarshalFromString(`{"UserID":1,"user_id":3,"USER-ID":2}`, &conflict))
+	should.Equal(Conflict{UserID: 1, UserId: 2, User_ID: 3}, conflict)
+}
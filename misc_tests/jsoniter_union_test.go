@@ -0,0 +1,116 @@
+package misc_tests
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+type unionShape interface {
+	Area() float64
+}
+
+type unionCircle struct {
+	Radius float64 `json:"radius"`
+}
+
+func (circle unionCircle) Area() float64 {
+	return 3 * circle.Radius * circle.Radius
+}
+
+type unionRect struct {
+	Kind   string  `json:"kind"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+func (rect *unionRect) Area() float64 {
+	return rect.Width * rect.Height
+}
+
+type unionEmpty struct {
+}
+
+func (empty unionEmpty) Area() float64 {
+	return 0
+}
+
+type unionCredentials interface {
+	Check() bool
+}
+
+type unionToken struct {
+	Token string `json:"token,redact"`
+}
+
+func (token unionToken) Check() bool {
+	return token.Token != ""
+}
+
+func init() {
+	jsoniter.RegisterUnion((*unionCredentials)(nil), "kind", map[string]interface{}{
+		"token": unionToken{},
+	})
+	jsoniter.RegisterUnion((*unionShape)(nil), "kind", map[string]interface{}{
+		"circle": unionCircle{},
+		"rect":   &unionRect{},
+		"empty":  unionEmpty{},
+	})
+}
+
+func Test_decode_union(t *testing.T) {
+	should := require.New(t)
+	type TestObject struct {
+		Shapes []unionShape
+		Shape  unionShape
+	}
+	var obj TestObject
+	should.NoError(jsoniter.UnmarshalFromString(`{"Shapes":[
+		{"kind":"circle","radius":1},
+		{"width":2,"kind":"rect","height":3},
+		{"kind":"empty"},
+		null
+	], "Shape": {"radius": 2, "kind": "circle"}}`, &obj))
+	should.Equal([]unionShape{unionCircle{1}, &unionRect{"rect", 2, 3}, unionEmpty{}, nil}, obj.Shapes)
+	should.Equal(unionCircle{2}, obj.Shape)
+	var shape unionShape
+	decoder := jsoniter.NewDecoder(bytes.NewBufferString(`{"width":2,"height":3,"kind":"rect"}`))
+	should.NoError(decoder.Decode(&shape))
+	should.Equal(&unionRect{"rect", 2, 3}, shape)
+}
+
+func Test_decode_union_errors(t *testing.T) {
+	should := require.New(t)
+	var shape unionShape
+	should.Error(jsoniter.UnmarshalFromString(`{"radius":1}`, &shape))
+	should.Error(jsoniter.UnmarshalFromString(`{"kind":"triangle"}`, &shape))
+	should.Error(jsoniter.UnmarshalFromString(`[]`, &shape))
+}
+
+func Test_encode_union(t *testing.T) {
+	should := require.New(t)
+	shapes := []unionShape{unionCircle{1}, &unionRect{"rect", 2, 3}, unionEmpty{}, nil}
+	output, err := jsoniter.MarshalToString(shapes)
+	should.NoError(err)
+	should.Equal(`[{"kind":"circle","radius":1},{"kind":"rect","width":2,"height":3},{"kind":"empty"},null]`, output)
+	var decoded []unionShape
+	should.NoError(jsoniter.UnmarshalFromString(output, &decoded))
+	should.Equal(shapes, decoded)
+	indented, err := jsoniter.MarshalIndent(struct{ Shape unionShape }{unionCircle{1}}, "", "  ")
+	should.NoError(err)
+	should.Equal("{\n  \"Shape\": {\n    \"kind\": \"circle\",\n    \"radius\": 1\n  }\n}", string(indented))
+}
+
+func Test_encode_union_redacted(t *testing.T) {
+	should := require.New(t)
+	type TestObject struct {
+		Credentials unionCredentials
+	}
+	stream := jsoniter.ConfigDefault.BorrowStream(nil)
+	defer jsoniter.ConfigDefault.ReturnStream(stream)
+	stream.SetRedaction(jsoniter.RedactMask)
+	stream.WriteVal(TestObject{unionToken{"hunter2"}})
+	should.Equal(`{"Credentials":{"kind":"token","token":"***"}}`, string(stream.Buffer()))
+}
@@ -0,0 +1,102 @@
+package jsoniter
+
+// Nullable is a value which can be absent, null or set.
+// As a struct field, absent is omitted when encoding, null is written as null.
+// When decoding, a missing field stays absent, null and value are recorded as such.
+type Nullable[T any] struct {
+	state uint8
+	value T
+}
+
+// NewNullable returns a Nullable set to value
+func NewNullable[T any](value T) Nullable[T] {
+	return Nullable[T]{state: valueSet, value: value}
+}
+
+// Null returns a Nullable explicitly set to null
+func Null[T any]() Nullable[T] {
+	return Nullable[T]{state: valueNull}
+}
+
+// IsPresent reports whether the value is null or set
+func (nullable Nullable[T]) IsPresent() bool {
+	return nullable.state != valueAbsent
+}
+
+// IsNull reports whether the value is explicitly null
+func (nullable Nullable[T]) IsNull() bool {
+	return nullable.state == valueNull
+}
+
+// IsSet reports whether there is a value
+func (nullable Nullable[T]) IsSet() bool {
+	return nullable.state == valueSet
+}
+
+// Get returns the value and whether it is set
+func (nullable Nullable[T]) Get() (T, bool) {
+	return nullable.value, nullable.state == valueSet
+}
+
+// Set sets the value
+func (nullable *Nullable[T]) Set(value T) {
+	nullable.state = valueSet
+	nullable.value = value
+}
+
+// SetNull sets the value to null
+func (nullable *Nullable[T]) SetNull() {
+	var zero T
+	nullable.state = valueNull
+	nullable.value = zero
+}
+
+// Unset makes the value absent
+func (nullable *Nullable[T]) Unset() {
+	var zero T
+	nullable.state = valueAbsent
+	nullable.value = zero
+}
+
+func (nullable *Nullable[T]) nullIsPresent() bool {
+	return true
+}
+
+// Optional is a value which can be absent or set, null is read as absent.
+// As a struct field, absent is omitted when encoding.
+type Optional[T any] struct {
+	state uint8
+	value T
+}
+
+// NewOptional returns an Optional set to value
+func NewOptional[T any](value T) Optional[T] {
+	return Optional[T]{state: valueSet, value: value}
+}
+
+// IsSet reports whether there is a value
+func (optional Optional[T]) IsSet() bool {
+	return optional.state == valueSet
+}
+
+// Get returns the value and whether it is set
+func (optional Optional[T]) Get() (T, bool) {
+	return optional.value, optional.state == valueSet
+}
+
+// Set sets the value
+func (optional *Optional[T]) Set(value T) {
+	optional.state = valueSet
+	optional.value = value
+}
+
+// Unset makes the value absent
+func (optional *Optional[T]) Unset() {
+	var zero T
+	optional.state = valueAbsent
+	optional.value = zero
+}
+
+func (optional *Optional[T]) nullIsPresent() bool {
+	return false
+}
@@ -25,6 +25,9 @@ func (cfg *frozenConfig) BorrowStream(writer io.Writer) *Stream {
 func (cfg *frozenConfig) ReturnStream(stream *Stream) {
 	stream.out = nil
 	stream.Error = nil
+	stream.indention = 0
+	stream.fieldMask = nil
+	stream.redaction = cfg.redaction
 	stream.Attachment = nil
 	cfg.streamPool.Put(stream)
 }
@@ -0,0 +1,78 @@
+package jsoniter
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/modern-go/reflect2"
+)
+
+// PrecompileError holds the errors of the codecs built by Precompile
+type PrecompileError struct {
+	Errors []error
+}
+
+func (err *PrecompileError) Error() string {
+	msgs := make([]string, len(err.Errors))
+	for i, e := range err.Errors {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Precompile builds and caches the encoders and decoders of the types of values, and of the types of their fields,
+// elements, keys and pointees. The unsupported types or tags, otherwise only reported when a value is encoded or decoded,
+// are returned as a *PrecompileError.
+func Precompile(api API, values ...interface{}) error {
+	cfg := api.(*frozenConfig)
+	var errors []error
+	visited := map[reflect.Type]bool{}
+	for _, val := range values {
+		if val == nil {
+			continue
+		}
+		typ := reflect2.TypeOf(val)
+		ptrType := reflect2.PtrTo(typ)
+		cfg.addDecoderToCache(ptrType.RType(), cfg.createDecoderOf(ptrType, &errors))
+		cfg.addEncoderToCache(typ.RType(), cfg.createEncoderOf(typ, &errors))
+		cfg.addEncoderToCache(ptrType.RType(), cfg.createEncoderOf(ptrType, &errors))
+		cfg.precompileElems(typ.Type1(), visited)
+	}
+	if len(errors) == 0 {
+		return nil
+	}
+	return &PrecompileError{Errors: errors}
+}
+
+// precompileElems caches the codecs of the types reachable from typ, their errors are already collected from typ
+func (cfg *frozenConfig) precompileElems(typ reflect.Type, visited map[reflect.Type]bool) {
+	if visited[typ] {
+		return
+	}
+	visited[typ] = true
+	var elems []reflect.Type
+	switch typ.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		elems = append(elems, typ.Elem())
+	case reflect.Map:
+		elems = append(elems, typ.Key(), typ.Elem())
+	case reflect.Struct:
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if (field.PkgPath != "" && !field.Anonymous) || field.Tag.Get(cfg.getTagKey()) == "-" {
+				continue
+			}
+			elems = append(elems, field.Type)
+		}
+	}
+	for _, elem := range elems {
+		switch elem.Kind() {
+		case reflect.Interface, reflect.Chan, reflect.Func, reflect.UnsafePointer, reflect.Complex64, reflect.Complex128:
+			continue
+		}
+		elemType := reflect2.Type2(elem)
+		cfg.DecoderOf(reflect2.PtrTo(elemType))
+		cfg.EncoderOf(elemType)
+		cfg.precompileElems(elem, visited)
+	}
+}
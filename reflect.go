@@ -36,17 +36,19 @@ type checkIsEmpty interface {
 
 type ctx struct {
 	*frozenConfig
-	prefix   string
-	encoders map[reflect2.Type]ValEncoder
-	decoders map[reflect2.Type]ValDecoder
+	prefix     string
+	encoders   map[reflect2.Type]ValEncoder
+	decoders   map[reflect2.Type]ValDecoder
+	projection *FieldMask                   // wanted fields of the decoded value, nil for all
+	projected  map[projectedType]ValDecoder // decoders specialised for a projection, by type and mask
+	errors     *[]error                     // errors of the codecs built, collected for Precompile if not nil
+	sources    *codecSources                // who supplied the codecs built, recorded for Explain if not nil
 }
 
-func (b *ctx) caseSensitive() bool {
-	if b.frozenConfig == nil {
-		// default is case-insensitive
-		return false
-	}
-	return b.frozenConfig.caseSensitive
+// projectedType keys the decoders specialised for a projection
+type projectedType struct {
+	typ        reflect2.Type
+	projection *FieldMask
 }
 
 func (b *ctx) append(prefix string) *ctx {
@@ -55,7 +57,42 @@ func (b *ctx) append(prefix string) *ctx {
 		prefix:       b.prefix + " " + prefix,
 		encoders:     b.encoders,
 		decoders:     b.decoders,
+		projection:   b.projection,
+		projected:    b.projected,
+		errors:       b.errors,
+		sources:      b.sources,
+	}
+}
+
+// unsupportedType is the error of the codecs of typ, naming the field path if any
+func (b *ctx) unsupportedType(typ reflect2.Type) error {
+	if b.prefix == "" {
+		return fmt.Errorf("%s is unsupported type", typ.String())
+	}
+	return fmt.Errorf("%s: %s is unsupported type", b.prefix[1:], typ.String())
+}
+
+// collectError records the error of a lazy error codec, to be reported by Precompile.
+// The errors name the field path, the same one is found by both the decoder and the encoder of a field.
+func (b *ctx) collectError(codec interface{}) {
+	if b.errors == nil {
+		return
+	}
+	var err error
+	switch codec := codec.(type) {
+	case *lazyErrorDecoder:
+		err = codec.err
+	case *lazyErrorEncoder:
+		err = codec.err
+	default:
+		return
+	}
+	for _, collected := range *b.errors {
+		if collected.Error() == err.Error() {
+			return
+		}
 	}
+	*b.errors = append(*b.errors, err)
 }
 
 // ReadVal copy the underlying JSON into go interface, same as json.Unmarshal
@@ -104,35 +141,42 @@ func (cfg *frozenConfig) DecoderOf(typ reflect2.Type) ValDecoder {
 	if decoder != nil {
 		return decoder
 	}
+	decoder = cfg.createDecoderOf(typ, nil)
+	cfg.addDecoderToCache(cacheKey, decoder)
+	return decoder
+}
+
+func (cfg *frozenConfig) createDecoderOf(typ reflect2.Type, errors *[]error) ValDecoder {
 	ctx := &ctx{
 		frozenConfig: cfg,
 		prefix:       "",
 		decoders:     map[reflect2.Type]ValDecoder{},
+		projected:    map[projectedType]ValDecoder{},
 		encoders:     map[reflect2.Type]ValEncoder{},
+		projection:   cfg.projection,
+		errors:       errors,
 	}
 	ptrType := typ.(*reflect2.UnsafePtrType)
-	decoder = decoderOfType(ctx, ptrType.Elem())
-	cfg.addDecoderToCache(cacheKey, decoder)
-	return decoder
+	return decoderOfType(ctx, ptrType.Elem())
 }
 
 func decoderOfType(ctx *ctx, typ reflect2.Type) ValDecoder {
-	decoder := getTypeDecoderFromExtension(ctx, typ)
-	if decoder != nil {
-		return decoder
-	}
-	decoder = createDecoderOfType(ctx, typ)
-	for _, extension := range extensions {
-		decoder = extension.DecorateDecoder(typ, decoder)
-	}
-	decoder = ctx.decoderExtension.DecorateDecoder(typ, decoder)
-	for _, extension := range ctx.extraExtensions {
-		decoder = extension.DecorateDecoder(typ, decoder)
-	}
-	return decoder
+	return decoderOfTypeFrom(ctx, typ, chainOf(ctx, ctx.decoderExtension))
 }
 
 func createDecoderOfType(ctx *ctx, typ reflect2.Type) ValDecoder {
+	if ctx.projection != nil {
+		// specialised for the projection, the value of a recursive type can be projected again by the same mask
+		key := projectedType{typ, ctx.projection}
+		if decoder := ctx.projected[key]; decoder != nil {
+			return decoder
+		}
+		placeholder := &placeholderDecoder{}
+		ctx.projected[key] = placeholder
+		decoder := _createDecoderOfType(ctx, typ)
+		placeholder.decoder = decoder
+		return decoder
+	}
 	decoder := ctx.decoders[typ]
 	if decoder != nil {
 		return decoder
@@ -141,6 +185,10 @@ func createDecoderOfType(ctx *ctx, typ reflect2.Type) ValDecoder {
 	ctx.decoders[typ] = placeholder
 	decoder = _createDecoderOfType(ctx, typ)
 	placeholder.decoder = decoder
+	if _, isErr := decoder.(*lazyErrorDecoder); isErr {
+		// built again for every field, the error names the field it is reported for
+		delete(ctx.decoders, typ)
+	}
 	return decoder
 }
 
@@ -153,6 +201,14 @@ func _createDecoderOfType(ctx *ctx, typ reflect2.Type) ValDecoder {
 	if decoder != nil {
 		return decoder
 	}
+	decoder = createDecoderOfTime(ctx, typ)
+	if decoder != nil {
+		return decoder
+	}
+	decoder = createDecoderOfOptionalValue(ctx, typ)
+	if decoder != nil {
+		return decoder
+	}
 	decoder = createDecoderOfMarshaler(ctx, typ)
 	if decoder != nil {
 		return decoder
@@ -167,6 +223,10 @@ func _createDecoderOfType(ctx *ctx, typ reflect2.Type) ValDecoder {
 	}
 	switch typ.Kind() {
 	case reflect.Interface:
+		decoder = createDecoderOfUnion(ctx, typ)
+		if decoder != nil {
+			return decoder
+		}
 		ifaceType, isIFace := typ.(*reflect2.UnsafeIFaceType)
 		if isIFace {
 			return &ifaceDecoder{valType: ifaceType}
@@ -183,7 +243,7 @@ func _createDecoderOfType(ctx *ctx, typ reflect2.Type) ValDecoder {
 	case reflect.Ptr:
 		return decoderOfOptional(ctx, typ)
 	default:
-		return &lazyErrorDecoder{err: fmt.Errorf("%s%s is unsupported type", ctx.prefix, typ.String())}
+		return &lazyErrorDecoder{err: ctx.unsupportedType(typ)}
 	}
 }
 
@@ -193,17 +253,24 @@ func (cfg *frozenConfig) EncoderOf(typ reflect2.Type) ValEncoder {
 	if encoder != nil {
 		return encoder
 	}
+	encoder = cfg.createEncoderOf(typ, nil)
+	cfg.addEncoderToCache(cacheKey, encoder)
+	return encoder
+}
+
+func (cfg *frozenConfig) createEncoderOf(typ reflect2.Type, errors *[]error) ValEncoder {
 	ctx := &ctx{
 		frozenConfig: cfg,
 		prefix:       "",
 		decoders:     map[reflect2.Type]ValDecoder{},
+		projected:    map[projectedType]ValDecoder{},
 		encoders:     map[reflect2.Type]ValEncoder{},
+		errors:       errors,
 	}
-	encoder = encoderOfType(ctx, typ)
+	encoder := encoderOfType(ctx, typ)
 	if typ.LikePtr() {
 		encoder = &onePtrEncoder{encoder}
 	}
-	cfg.addEncoderToCache(cacheKey, encoder)
 	return encoder
 }
 
@@ -220,19 +287,7 @@ func (encoder *onePtrEncoder) Encode(ptr unsafe.Pointer, stream *Stream) {
 }
 
 func encoderOfType(ctx *ctx, typ reflect2.Type) ValEncoder {
-	encoder := getTypeEncoderFromExtension(ctx, typ)
-	if encoder != nil {
-		return encoder
-	}
-	encoder = createEncoderOfType(ctx, typ)
-	for _, extension := range extensions {
-		encoder = extension.DecorateEncoder(typ, encoder)
-	}
-	encoder = ctx.encoderExtension.DecorateEncoder(typ, encoder)
-	for _, extension := range ctx.extraExtensions {
-		encoder = extension.DecorateEncoder(typ, encoder)
-	}
-	return encoder
+	return encoderOfTypeFrom(ctx, typ, chainOf(ctx, ctx.encoderExtension))
 }
 
 func createEncoderOfType(ctx *ctx, typ reflect2.Type) ValEncoder {
@@ -244,6 +299,10 @@ func createEncoderOfType(ctx *ctx, typ reflect2.Type) ValEncoder {
 	ctx.encoders[typ] = placeholder
 	encoder = _createEncoderOfType(ctx, typ)
 	placeholder.encoder = encoder
+	if _, isErr := encoder.(*lazyErrorEncoder); isErr {
+		// built again for every field, the error names the field it is reported for
+		delete(ctx.encoders, typ)
+	}
 	return encoder
 }
 func _createEncoderOfType(ctx *ctx, typ reflect2.Type) ValEncoder {
@@ -255,6 +314,14 @@ func _createEncoderOfType(ctx *ctx, typ reflect2.Type) ValEncoder {
 	if encoder != nil {
 		return encoder
 	}
+	encoder = createEncoderOfTime(ctx, typ)
+	if encoder != nil {
+		return encoder
+	}
+	encoder = createEncoderOfOptionalValue(ctx, typ)
+	if encoder != nil {
+		return encoder
+	}
 	encoder = createEncoderOfMarshaler(ctx, typ)
 	if encoder != nil {
 		return encoder
@@ -270,6 +337,10 @@ func _createEncoderOfType(ctx *ctx, typ reflect2.Type) ValEncoder {
 	kind := typ.Kind()
 	switch kind {
 	case reflect.Interface:
+		encoder = createEncoderOfUnion(ctx, typ)
+		if encoder != nil {
+			return encoder
+		}
 		return &dynamicEncoder{typ}
 	case reflect.Struct:
 		return encoderOfStruct(ctx, typ)
@@ -282,7 +353,7 @@ func _createEncoderOfType(ctx *ctx, typ reflect2.Type) ValEncoder {
 	case reflect.Ptr:
 		return encoderOfOptional(ctx, typ)
 	default:
-		return &lazyErrorEncoder{err: fmt.Errorf("%s%s is unsupported type", ctx.prefix, typ.String())}
+		return &lazyErrorEncoder{err: ctx.unsupportedType(typ)}
 	}
 }
 
@@ -55,6 +55,29 @@ type Extension interface {
 	DecorateEncoder(typ reflect2.Type, encoder ValEncoder) ValEncoder
 }
 
+// PrioritizedExtension is an Extension with a priority, 0 when not implemented.
+// The extensions of higher priority create the codecs first, whether registered globally or on a config.
+type PrioritizedExtension interface {
+	Extension
+	Priority() int
+}
+
+// DecoderChainExtension is an Extension creating decoders out of the decoder that would be used without it.
+// next creates this decoder, from the extensions after it or by reflection.
+// Return nil without calling next to leave the type to the extensions after it.
+type DecoderChainExtension interface {
+	Extension
+	ChainDecoder(typ reflect2.Type, next func() ValDecoder) ValDecoder
+}
+
+// EncoderChainExtension is an Extension creating encoders out of the encoder that would be used without it.
+// next creates this encoder, from the extensions after it or by reflection.
+// Return nil without calling next to leave the type to the extensions after it.
+type EncoderChainExtension interface {
+	Extension
+	ChainEncoder(typ reflect2.Type, next func() ValEncoder) ValEncoder
+}
+
 // DummyExtension embed this type get dummy implementation for all methods of Extension
 type DummyExtension struct {
 }
@@ -235,43 +258,147 @@ func RegisterFieldEncoder(typ string, field string, encoder ValEncoder) {
 	fieldEncoders[fmt.Sprintf("%s/%s", typ, field)] = encoder
 }
 
+// RegisterDecoderOf register decoder for typ on api only, typ is matched by identity rather than by name.
+// The decoders already built by api are dropped, so that they see the new one.
+// Like the other codecs registered on an API, it is setup: api must not be in use by other goroutines meanwhile.
+func RegisterDecoderOf(api API, typ reflect.Type, decoder ValDecoder) {
+	cfg := api.(*frozenConfig)
+	decoderExtension := DecoderExtension{}
+	for k, v := range cfg.decoderExtension.(DecoderExtension) {
+		decoderExtension[k] = v
+	}
+	decoderExtension[reflect2.Type2(typ)] = decoder
+	cfg.decoderExtension = decoderExtension
+	registeredDecoders := DecoderExtension{}
+	for k, v := range cfg.registeredDecoders {
+		registeredDecoders[k] = v
+	}
+	registeredDecoders[reflect2.Type2(typ)] = decoder
+	cfg.registeredDecoders = registeredDecoders
+	cfg.initCache()
+}
+
+// RegisterEncoderOf register encoder for typ on api only, typ is matched by identity rather than by name.
+// The encoders already built by api are dropped, so that they see the new one.
+// Like the other codecs registered on an API, it is setup: api must not be in use by other goroutines meanwhile.
+func RegisterEncoderOf(api API, typ reflect.Type, encoder ValEncoder) {
+	cfg := api.(*frozenConfig)
+	encoderExtension := EncoderExtension{}
+	for k, v := range cfg.encoderExtension.(EncoderExtension) {
+		encoderExtension[k] = v
+	}
+	encoderExtension[reflect2.Type2(typ)] = encoder
+	cfg.encoderExtension = encoderExtension
+	registeredEncoders := EncoderExtension{}
+	for k, v := range cfg.registeredEncoders {
+		registeredEncoders[k] = v
+	}
+	registeredEncoders[reflect2.Type2(typ)] = encoder
+	cfg.registeredEncoders = registeredEncoders
+	cfg.initCache()
+}
+
+type fieldCodecKey struct {
+	rtype uintptr
+	field string
+}
+
+// RegisterFieldDecoderOf register decoder for a field of the struct typ on api only, as setup like RegisterDecoderOf
+func RegisterFieldDecoderOf(api API, typ reflect.Type, field string, decoder ValDecoder) {
+	cfg := api.(*frozenConfig)
+	fieldDecoders := map[fieldCodecKey]ValDecoder{}
+	for k, v := range cfg.fieldDecoders {
+		fieldDecoders[k] = v
+	}
+	fieldDecoders[fieldCodecKey{reflect2.Type2(typ).RType(), field}] = decoder
+	cfg.fieldDecoders = fieldDecoders
+	cfg.initCache()
+}
+
+// RegisterFieldEncoderOf register encoder for a field of the struct typ on api only, as setup like RegisterEncoderOf
+func RegisterFieldEncoderOf(api API, typ reflect.Type, field string, encoder ValEncoder) {
+	cfg := api.(*frozenConfig)
+	fieldEncoders := map[fieldCodecKey]ValEncoder{}
+	for k, v := range cfg.fieldEncoders {
+		fieldEncoders[k] = v
+	}
+	fieldEncoders[fieldCodecKey{reflect2.Type2(typ).RType(), field}] = encoder
+	cfg.fieldEncoders = fieldEncoders
+	cfg.initCache()
+}
+
 // RegisterExtension register extension
 func RegisterExtension(extension Extension) {
 	extensions = append(extensions, extension)
 }
 
-func getTypeDecoderFromExtension(ctx *ctx, typ reflect2.Type) ValDecoder {
-	decoder := _getTypeDecoderFromExtension(ctx, typ)
-	if decoder != nil {
-		for _, extension := range extensions {
-			decoder = extension.DecorateDecoder(typ, decoder)
-		}
-		decoder = ctx.decoderExtension.DecorateDecoder(typ, decoder)
-		for _, extension := range ctx.extraExtensions {
-			decoder = extension.DecorateDecoder(typ, decoder)
-		}
-	}
-	return decoder
+// chainOf returns the extensions creating codecs, in order: highest priority first,
+// then global extensions, the config own one and the extensions registered on the config.
+func chainOf(ctx *ctx, configExtension Extension) []Extension {
+	chain := make([]Extension, 0, len(extensions)+1+len(ctx.extraExtensions))
+	chain = append(chain, extensions...)
+	chain = append(chain, configExtension)
+	chain = append(chain, ctx.extraExtensions...)
+	sort.SliceStable(chain, func(i, j int) bool {
+		return priorityOf(chain[i]) > priorityOf(chain[j])
+	})
+	return chain
 }
-func _getTypeDecoderFromExtension(ctx *ctx, typ reflect2.Type) ValDecoder {
-	for _, extension := range extensions {
+
+func priorityOf(extension Extension) int {
+	if prioritized, ok := extension.(PrioritizedExtension); ok {
+		return prioritized.Priority()
+	}
+	return 0
+}
+
+func decoderOfTypeFrom(ctx *ctx, typ reflect2.Type, chain []Extension) ValDecoder {
+	for i, extension := range chain {
+		if chained, ok := extension.(DecoderChainExtension); ok {
+			rest := chain[i+1:]
+			decoder := chained.ChainDecoder(typ, func() ValDecoder {
+				return decoderOfTypeFrom(ctx, typ, rest)
+			})
+			if decoder != nil {
+				ctx.sources.decoderFrom(typ, extensionName(extension))
+				return decoder
+			}
+		}
 		decoder := extension.CreateDecoder(typ)
 		if decoder != nil {
-			return decoder
+			ctx.sources.decoderFrom(typ, extensionName(extension))
+			return decorateDecoder(ctx, typ, decoder)
 		}
 	}
-	decoder := ctx.decoderExtension.CreateDecoder(typ)
-	if decoder != nil {
-		return decoder
+	decoder := getTypeDecoderFromRegistry(typ)
+	if decoder == nil {
+		decoder = createDecoderOfType(ctx, typ)
+		ctx.collectError(decoder)
+	} else {
+		ctx.sources.decoderFrom(typ, "RegisterTypeDecoder")
 	}
+	return decorateDecoder(ctx, typ, decoder)
+}
+
+func decorateDecoder(ctx *ctx, typ reflect2.Type, decoder ValDecoder) ValDecoder {
+	for _, extension := range extensions {
+		decoder = decorateDecoderWith(ctx, extension, typ, decoder)
+	}
+	decoder = decorateDecoderWith(ctx, ctx.decoderExtension, typ, decoder)
 	for _, extension := range ctx.extraExtensions {
-		decoder := extension.CreateDecoder(typ)
-		if decoder != nil {
-			return decoder
-		}
+		decoder = decorateDecoderWith(ctx, extension, typ, decoder)
 	}
-	typeName := typ.String()
-	decoder = typeDecoders[typeName]
+	return decoder
+}
+
+func decorateDecoderWith(ctx *ctx, extension Extension, typ reflect2.Type, decoder ValDecoder) ValDecoder {
+	decorated := extension.DecorateDecoder(typ, decoder)
+	ctx.sources.decorated(typ, extension, decoder, decorated)
+	return decorated
+}
+
+func getTypeDecoderFromRegistry(typ reflect2.Type) ValDecoder {
+	decoder := typeDecoders[typ.String()]
 	if decoder != nil {
 		return decoder
 	}
@@ -285,39 +412,53 @@ func _getTypeDecoderFromExtension(ctx *ctx, typ reflect2.Type) ValDecoder {
 	return nil
 }
 
-func getTypeEncoderFromExtension(ctx *ctx, typ reflect2.Type) ValEncoder {
-	encoder := _getTypeEncoderFromExtension(ctx, typ)
-	if encoder != nil {
-		for _, extension := range extensions {
-			encoder = extension.DecorateEncoder(typ, encoder)
+func encoderOfTypeFrom(ctx *ctx, typ reflect2.Type, chain []Extension) ValEncoder {
+	for i, extension := range chain {
+		if chained, ok := extension.(EncoderChainExtension); ok {
+			rest := chain[i+1:]
+			encoder := chained.ChainEncoder(typ, func() ValEncoder {
+				return encoderOfTypeFrom(ctx, typ, rest)
+			})
+			if encoder != nil {
+				ctx.sources.encoderFrom(typ, extensionName(extension))
+				return encoder
+			}
 		}
-		encoder = ctx.encoderExtension.DecorateEncoder(typ, encoder)
-		for _, extension := range ctx.extraExtensions {
-			encoder = extension.DecorateEncoder(typ, encoder)
+		encoder := extension.CreateEncoder(typ)
+		if encoder != nil {
+			ctx.sources.encoderFrom(typ, extensionName(extension))
+			return decorateEncoder(ctx, typ, encoder)
 		}
 	}
-	return encoder
+	encoder := getTypeEncoderFromRegistry(typ)
+	if encoder == nil {
+		encoder = createEncoderOfType(ctx, typ)
+		ctx.collectError(encoder)
+	} else {
+		ctx.sources.encoderFrom(typ, "RegisterTypeEncoder")
+	}
+	return decorateEncoder(ctx, typ, encoder)
 }
 
-func _getTypeEncoderFromExtension(ctx *ctx, typ reflect2.Type) ValEncoder {
+func decorateEncoder(ctx *ctx, typ reflect2.Type, encoder ValEncoder) ValEncoder {
 	for _, extension := range extensions {
-		encoder := extension.CreateEncoder(typ)
-		if encoder != nil {
-			return encoder
-		}
-	}
-	encoder := ctx.encoderExtension.CreateEncoder(typ)
-	if encoder != nil {
-		return encoder
+		encoder = decorateEncoderWith(ctx, extension, typ, encoder)
 	}
+	encoder = decorateEncoderWith(ctx, ctx.encoderExtension, typ, encoder)
 	for _, extension := range ctx.extraExtensions {
-		encoder := extension.CreateEncoder(typ)
-		if encoder != nil {
-			return encoder
-		}
+		encoder = decorateEncoderWith(ctx, extension, typ, encoder)
 	}
-	typeName := typ.String()
-	encoder = typeEncoders[typeName]
+	return encoder
+}
+
+func decorateEncoderWith(ctx *ctx, extension Extension, typ reflect2.Type, encoder ValEncoder) ValEncoder {
+	decorated := extension.DecorateEncoder(typ, encoder)
+	ctx.sources.decorated(typ, extension, encoder, decorated)
+	return decorated
+}
+
+func getTypeEncoderFromRegistry(typ reflect2.Type) ValEncoder {
+	encoder := typeEncoders[typ.String()]
 	if encoder != nil {
 		return encoder
 	}
@@ -341,7 +482,7 @@ func describeStruct(ctx *ctx, typ reflect2.Type) *StructDescriptor {
 		if ctx.onlyTaggedField && !hastag && !field.Anonymous() {
 			continue
 		}
-		if tag == "-" || field.Name() == "_" {
+		if tag == "-" || field.Name() == "_" || field.Type() == fieldSetType {
 			continue
 		}
 		tagParts := strings.Split(tag, ",")
@@ -352,7 +493,7 @@ func describeStruct(ctx *ctx, typ reflect2.Type) *StructDescriptor {
 					binding.levels = append([]int{i}, binding.levels...)
 					omitempty := binding.Encoder.(*structFieldEncoder).omitempty
 					binding.Encoder = &structFieldEncoder{field, binding.Encoder, omitempty}
-					binding.Decoder = &structFieldDecoder{field, binding.Decoder}
+					binding.Decoder = &structFieldDecoder{field: field, fieldDecoder: binding.Decoder}
 					embeddedBindings = append(embeddedBindings, binding)
 				}
 				continue
@@ -366,7 +507,7 @@ func describeStruct(ctx *ctx, typ reflect2.Type) *StructDescriptor {
 						binding.Encoder = &dereferenceEncoder{binding.Encoder}
 						binding.Encoder = &structFieldEncoder{field, binding.Encoder, omitempty}
 						binding.Decoder = &dereferenceDecoder{ptrType.Elem(), binding.Decoder}
-						binding.Decoder = &structFieldDecoder{field, binding.Decoder}
+						binding.Decoder = &structFieldDecoder{field: field, fieldDecoder: binding.Decoder}
 						embeddedBindings = append(embeddedBindings, binding)
 					}
 					continue
@@ -375,13 +516,31 @@ func describeStruct(ctx *ctx, typ reflect2.Type) *StructDescriptor {
 		}
 		fieldNames := calcFieldNames(field.Name(), tagParts[0], tag)
 		fieldCacheKey := fmt.Sprintf("%s/%s", typ.String(), field.Name())
-		decoder := fieldDecoders[fieldCacheKey]
+		decoder := ctx.fieldDecoders[fieldCodecKey{typ.RType(), field.Name()}]
 		if decoder == nil {
-			decoder = decoderOfType(ctx.append(field.Name()), field.Type())
+			decoder = fieldDecoders[fieldCacheKey]
+		}
+		// the codecs of unexported fields are never used, their errors are not reported
+		var fieldErrors *[]error
+		if len(fieldNames) > 0 {
+			fieldErrors = ctx.errors
+		}
+		if decoder == nil {
+			fieldCtx := ctx.append(field.Name())
+			fieldCtx.errors = fieldErrors
+			if ctx.projection != nil && len(fieldNames) > 0 {
+				fieldCtx.projection = ctx.projection.childOf(append(fieldNames[:len(fieldNames):len(fieldNames)], tagAliases(tagParts)...))
+			}
+			decoder = decoderOfType(fieldCtx, field.Type())
 		}
-		encoder := fieldEncoders[fieldCacheKey]
+		encoder := ctx.fieldEncoders[fieldCodecKey{typ.RType(), field.Name()}]
 		if encoder == nil {
-			encoder = encoderOfType(ctx.append(field.Name()), field.Type())
+			encoder = fieldEncoders[fieldCacheKey]
+		}
+		if encoder == nil {
+			fieldCtx := ctx.append(field.Name())
+			fieldCtx.errors = fieldErrors
+			encoder = encoderOfType(fieldCtx, field.Type())
 		}
 		binding := &Binding{
 			Field:     field,
@@ -401,14 +560,14 @@ func createStructDescriptor(ctx *ctx, typ reflect2.Type, bindings []*Binding, em
 		Fields: bindings,
 	}
 	for _, extension := range extensions {
-		extension.UpdateStructDescriptor(structDescriptor)
+		ctx.sources.updateStructDescriptor(extension, structDescriptor)
 	}
-	ctx.encoderExtension.UpdateStructDescriptor(structDescriptor)
-	ctx.decoderExtension.UpdateStructDescriptor(structDescriptor)
+	ctx.sources.updateStructDescriptor(ctx.encoderExtension, structDescriptor)
+	ctx.sources.updateStructDescriptor(ctx.decoderExtension, structDescriptor)
 	for _, extension := range ctx.extraExtensions {
-		extension.UpdateStructDescriptor(structDescriptor)
+		ctx.sources.updateStructDescriptor(extension, structDescriptor)
 	}
-	processTags(structDescriptor, ctx.frozenConfig)
+	processTags(structDescriptor, ctx)
 	// merge normal & embedded bindings & sort with original order
 	allBindings := sortableBindings(append(embeddedBindings, structDescriptor.Fields...))
 	sort.Sort(allBindings)
@@ -440,13 +599,57 @@ func (bindings sortableBindings) Swap(i, j int) {
 	bindings[i], bindings[j] = bindings[j], bindings[i]
 }
 
-func processTags(structDescriptor *StructDescriptor, cfg *frozenConfig) {
+// tagAliases returns the names of the alias= options of a field tag
+func tagAliases(tagParts []string) []string {
+	var aliases []string
+	for _, tagPart := range tagParts[1:] {
+		if strings.HasPrefix(tagPart, "alias=") {
+			aliases = append(aliases, tagPart[len("alias="):])
+		}
+	}
+	return aliases
+}
+
+func processTags(structDescriptor *StructDescriptor, ctx *ctx) {
+	cfg := ctx.frozenConfig
 	for _, binding := range structDescriptor.Fields {
-		shouldOmitEmpty := false
+		// absent Nullable and Optional are never written
+		fieldEncoder := binding.Encoder
+		if placeholder, isPlaceholder := fieldEncoder.(*placeholderEncoder); isPlaceholder {
+			// built already while describing a recursive type
+			fieldEncoder = placeholder.encoder
+		}
+		_, shouldOmitEmpty := fieldEncoder.(*optionalValueEncoder)
 		tagParts := strings.Split(binding.Field.Tag().Get(cfg.getTagKey()), ",")
+		for i, tagPart := range tagParts[1:] {
+			if strings.HasPrefix(tagPart, "format:") {
+				binding.Decoder, binding.Encoder = decorateWithFormat(binding.Field.Type(),
+					tagPart[len("format:"):], tagParts[i+2:], binding.Decoder, binding.Encoder)
+			} else if strings.HasPrefix(tagPart, "precision=") {
+				binding.Decoder, binding.Encoder = decorateWithPrecision(binding.Field.Type(),
+					tagPart[len("precision="):], binding.Decoder, binding.Encoder)
+			}
+		}
+		if len(binding.FromNames) > 0 || len(binding.ToNames) > 0 {
+			ctx.collectError(binding.Decoder)
+			ctx.collectError(binding.Encoder)
+		}
+		readOnly, writeOnly, redact := false, false, false
 		for _, tagPart := range tagParts[1:] {
 			if tagPart == "omitempty" {
 				shouldOmitEmpty = true
+			} else if tagPart == "redact" {
+				redact = true
+			} else if tagPart == "readonly" {
+				readOnly = true
+			} else if tagPart == "writeonly" {
+				writeOnly = true
+			} else if strings.HasPrefix(tagPart, "alias=") {
+				// extra names accepted when decoding, ignored or private field stays so
+				if len(binding.FromNames) > 0 {
+					fromNames := binding.FromNames
+					binding.FromNames = append(fromNames[:len(fromNames):len(fromNames)], tagPart[len("alias="):])
+				}
 			} else if tagPart == "string" {
 				if binding.Field.Type().Kind() == reflect.String {
 					binding.Decoder = &stringModeStringDecoder{binding.Decoder, cfg}
@@ -457,11 +660,44 @@ func processTags(structDescriptor *StructDescriptor, cfg *frozenConfig) {
 				}
 			}
 		}
-		binding.Decoder = &structFieldDecoder{binding.Field, binding.Decoder}
+		if writeOnly {
+			binding.ToNames = []string{}
+		}
+		if redact {
+			binding.Encoder = &redactEncoder{binding.Encoder}
+		}
+		if readOnly {
+			if cfg.disallowReadOnlyFields {
+				binding.Decoder = &readOnlyFieldDecoder{}
+			} else {
+				binding.FromNames = []string{}
+			}
+		}
+		if !cfg.inGroups(binding.Field) {
+			binding.ToNames = []string{}
+			if cfg.decodeByGroups {
+				binding.FromNames = []string{}
+			}
+		}
+		binding.Decoder = &structFieldDecoder{field: binding.Field, fieldDecoder: binding.Decoder}
 		binding.Encoder = &structFieldEncoder{binding.Field, binding.Encoder, shouldOmitEmpty}
 	}
 }
 
+// inGroups tells if the field belongs to one of the active groups, always true without groups
+func (cfg *frozenConfig) inGroups(field reflect2.StructField) bool {
+	tag, hasTag := field.Tag().Lookup("groups")
+	if cfg.groups == nil || !hasTag {
+		return true
+	}
+	for _, group := range strings.Split(tag, ",") {
+		if cfg.groups[strings.TrimSpace(group)] {
+			return true
+		}
+	}
+	return false
+}
+
 func calcFieldNames(originalFieldName string, tagProvidedFieldName string, wholeTag string) []string {
 	// ignore?
 	if wholeTag == "-" {
@@ -0,0 +1,38 @@
+package jsoniter
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// RegisterCodec register decoder and encoder for T on api only, either can be nil.
+// It is setup, see RegisterDecoderOf.
+func RegisterCodec[T any](api API, decoder ValDecoder, encoder ValEncoder) {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	if decoder != nil {
+		RegisterDecoderOf(api, typ, decoder)
+	}
+	if encoder != nil {
+		RegisterEncoderOf(api, typ, encoder)
+	}
+}
+
+// RegisterDecoderFunc register a typed decode function for T on api only
+func RegisterDecoderFunc[T any](api API, fun func(val *T, iter *Iterator)) {
+	RegisterDecoderOf(api, reflect.TypeOf((*T)(nil)).Elem(), &funcDecoder{func(ptr unsafe.Pointer, iter *Iterator) {
+		fun((*T)(ptr), iter)
+	}})
+}
+
+// RegisterEncoderFunc register a typed encode function for T on api only, isEmpty can be nil
+func RegisterEncoderFunc[T any](api API, fun func(val *T, stream *Stream), isEmpty func(val *T) bool) {
+	encoder := &funcEncoder{fun: func(ptr unsafe.Pointer, stream *Stream) {
+		fun((*T)(ptr), stream)
+	}}
+	if isEmpty != nil {
+		encoder.isEmptyFunc = func(ptr unsafe.Pointer) bool {
+			return isEmpty((*T)(ptr))
+		}
+	}
+	RegisterEncoderOf(api, reflect.TypeOf((*T)(nil)).Elem(), encoder)
+}
@@ -0,0 +1,227 @@
+package jsoniter
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// FieldMatching tells how the object field names are matched to the struct fields when decoding.
+// An exact match always wins over the other ones.
+type FieldMatching int
+
+const (
+	// FieldMatchDefault is FieldMatchExact with Config.CaseSensitive, otherwise FieldMatchUnicodeFold
+	FieldMatchDefault FieldMatching = iota
+	// FieldMatchExact matches only identical names
+	FieldMatchExact
+	// FieldMatchASCIIFold ignores the case of ASCII letters
+	FieldMatchASCIIFold
+	// FieldMatchUnicodeFold ignores the case with Unicode case-folding, like encoding/json
+	FieldMatchUnicodeFold
+	// FieldMatchIgnoreSeparators is FieldMatchUnicodeFold also ignoring '_' and '-', user_id matches UserID
+	FieldMatchIgnoreSeparators
+)
+
+// canonicalFieldName returns the form shared by all the names matching name
+func canonicalFieldName(name string, matching FieldMatching) string {
+	if matching == FieldMatchExact {
+		return name
+	}
+	var canonical strings.Builder
+	for _, r := range name {
+		if matching == FieldMatchIgnoreSeparators && (r == '_' || r == '-') {
+			continue
+		}
+		if r < utf8.RuneSelf || matching == FieldMatchASCIIFold {
+			if 'A' <= r && r <= 'Z' {
+				r += 'a' - 'A'
+			}
+			canonical.WriteRune(r)
+			continue
+		}
+		canonical.WriteRune(canonicalRune(r))
+	}
+	return canonical.String()
+}
+
+// canonicalRune picks one rune of the case-folding orbit of r, the lower case ASCII letter if any
+func canonicalRune(r rune) rune {
+	canonical := r
+	for folded := unicode.SimpleFold(r); folded != r; folded = unicode.SimpleFold(folded) {
+		if folded < utf8.RuneSelf {
+			return unicode.ToLower(folded)
+		}
+		if folded < canonical {
+			canonical = folded
+		}
+	}
+	return canonical
+}
+
+// fieldDispatch is a perfect hash table of the field names of one struct.
+// The seed is searched when the decoder is created, so that no two names share a slot.
+type fieldDispatch struct {
+	seed     uint32
+	mask     uint32
+	entries  []fieldDispatchEntry
+	matching FieldMatching
+	// names matching exactly before the other matches, only set when they would match another field
+	exact *fieldDispatch
+}
+
+type fieldDispatchEntry struct {
+	name    string
+	decoder *structFieldDecoder
+}
+
+func newFieldDispatch(fields map[string]*structFieldDecoder, matching FieldMatching) *fieldDispatch {
+	if matching == FieldMatchExact {
+		return buildFieldDispatch(fields, FieldMatchExact)
+	}
+	canonicalFields := map[string]*structFieldDecoder{}
+	canonicalNames := map[string]string{}
+	for name, decoder := range fields {
+		canonical := canonicalFieldName(name, matching)
+		old := canonicalFields[canonical]
+		// prefer the name already in canonical form, then the field declared first
+		if old == nil || preferFieldName(name, decoder, canonicalNames[canonical], old, canonical) {
+			canonicalFields[canonical] = decoder
+			canonicalNames[canonical] = name
+		}
+	}
+	exact := map[string]*structFieldDecoder{}
+	for name, decoder := range fields {
+		if canonicalFields[canonicalFieldName(name, matching)] != decoder {
+			exact[name] = decoder
+		}
+	}
+	dispatch := buildFieldDispatch(canonicalFields, matching)
+	if len(exact) > 0 {
+		dispatch.exact = buildFieldDispatch(exact, FieldMatchExact)
+	}
+	return dispatch
+}
+
+func preferFieldName(name string, decoder *structFieldDecoder, oldName string, oldDecoder *structFieldDecoder, canonical string) bool {
+	if (name == canonical) != (oldName == canonical) {
+		return name == canonical
+	}
+	if decoder.field.Offset() != oldDecoder.field.Offset() {
+		return decoder.field.Offset() < oldDecoder.field.Offset()
+	}
+	return name < oldName
+}
+
+func buildFieldDispatch(fields map[string]*structFieldDecoder, matching FieldMatching) *fieldDispatch {
+	size := uint32(1)
+	for size < uint32(2*len(fields)) {
+		size <<= 1
+	}
+	for seed := uint32(1); ; seed++ {
+		if seed%64 == 0 {
+			// too crowded, give the names more room
+			size <<= 1
+		}
+		dispatch := &fieldDispatch{
+			seed:     seed,
+			mask:     size - 1,
+			entries:  make([]fieldDispatchEntry, size),
+			matching: matching,
+		}
+		if dispatch.fill(fields) {
+			return dispatch
+		}
+	}
+}
+
+func (dispatch *fieldDispatch) fill(fields map[string]*structFieldDecoder) bool {
+	for name, decoder := range fields {
+		entry := &dispatch.entries[dispatch.hash([]byte(name))&dispatch.mask]
+		if entry.decoder != nil {
+			return false
+		}
+		entry.name = name
+		entry.decoder = decoder
+	}
+	return true
+}
+
+// asciiCanonical applies the matching to one ASCII byte, false if the byte is ignored
+func (dispatch *fieldDispatch) asciiCanonical(b byte) (byte, bool) {
+	switch dispatch.matching {
+	case FieldMatchExact:
+		return b, true
+	case FieldMatchIgnoreSeparators:
+		if b == '_' || b == '-' {
+			return b, false
+		}
+	}
+	if 'A' <= b && b <= 'Z' {
+		b += 'a' - 'A'
+	}
+	return b, true
+}
+
+// hash is FNV-1a started from the seed, over the canonical form of an ASCII name
+func (dispatch *fieldDispatch) hash(name []byte) uint32 {
+	hash := uint32(0x811c9dc5) ^ dispatch.seed*0x9e3779b9
+	for _, b := range name {
+		b, kept := dispatch.asciiCanonical(b)
+		if !kept {
+			continue
+		}
+		hash ^= uint32(b)
+		hash *= 0x1000193
+	}
+	hash ^= hash >> 16
+	hash *= 0x85ebca6b
+	hash ^= hash >> 13
+	return hash
+}
+
+func (dispatch *fieldDispatch) lookup(name []byte) *structFieldDecoder {
+	if dispatch.exact != nil {
+		if decoder := dispatch.exact.lookup(name); decoder != nil {
+			return decoder
+		}
+	}
+	if dispatch.matching == FieldMatchUnicodeFold || dispatch.matching == FieldMatchIgnoreSeparators {
+		if !isASCII(name) {
+			// unicode letters are not folded by hash, fold them the slow way
+			name = []byte(canonicalFieldName(string(name), dispatch.matching))
+		}
+	}
+	entry := &dispatch.entries[dispatch.hash(name)&dispatch.mask]
+	if entry.decoder != nil && dispatch.matches(entry.name, name) {
+		return entry.decoder
+	}
+	return nil
+}
+
+func (dispatch *fieldDispatch) matches(expected string, name []byte) bool {
+	if dispatch.matching == FieldMatchExact {
+		return expected == string(name)
+	}
+	i := 0
+	for _, b := range name {
+		b, kept := dispatch.asciiCanonical(b)
+		if !kept {
+			continue
+		}
+		if i >= len(expected) || expected[i] != b {
+			return false
+		}
+		i++
+	}
+	return i == len(expected)
+}
+
+func isASCII(name []byte) bool {
+	for _, b := range name {
+		if b >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
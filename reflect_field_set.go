@@ -0,0 +1,56 @@
+package jsoniter
+
+import (
+	"sort"
+	"unsafe"
+
+	"github.com/modern-go/reflect2"
+)
+
+// FieldSet records the struct fields present in the input, by their Go field name.
+// Declare a field of type FieldSet in the struct, it is refilled on every decode of the struct.
+// Fields promoted from embedded structs are recorded by their own name.
+// The FieldSet field itself is never encoded or decoded.
+type FieldSet map[string]struct{}
+
+// Has tells if the field was present in the input
+func (fieldSet FieldSet) Has(fieldName string) bool {
+	_, found := fieldSet[fieldName]
+	return found
+}
+
+// Names returns the present fields, sorted
+func (fieldSet FieldSet) Names() []string {
+	names := make([]string, 0, len(fieldSet))
+	for name := range fieldSet {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var fieldSetType = reflect2.TypeOfPtr((*FieldSet)(nil)).Elem()
+
+func fieldSetOf(typ reflect2.Type) reflect2.StructField {
+	structType := typ.(*reflect2.UnsafeStructType)
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if field.Type() == fieldSetType {
+			return field
+		}
+	}
+	return nil
+}
+
+type fieldSetDecoder struct {
+	fieldSet reflect2.StructField
+	decoder  ValDecoder
+}
+
+func (decoder *fieldSetDecoder) Decode(ptr unsafe.Pointer, iter *Iterator) {
+	if iter.ReadNil() {
+		return
+	}
+	*(*FieldSet)(decoder.fieldSet.UnsafeGet(ptr)) = FieldSet{}
+	decoder.decoder.Decode(ptr, iter)
+}
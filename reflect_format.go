@@ -0,0 +1,348 @@
+package jsoniter
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+	"unsafe"
+
+	"github.com/modern-go/reflect2"
+)
+
+var timeType = reflect2.TypeOfPtr((*time.Time)(nil)).Elem()
+var durationType = reflect2.TypeOfPtr((*time.Duration)(nil)).Elem()
+
+var fieldFormats = map[string]FieldFormat{}
+
+// FieldFormat customizes the wire format of struct fields tagged with format:<name>.
+// time.Time fields also accept a Go time layout as name, one without a comma, which separates the tag options.
+// The default codec of the field is passed in, so a format can delegate to it (IsEmpty for example).
+// Return nil if the format does not support the field type.
+type FieldFormat interface {
+	DecorateDecoder(typ reflect2.Type, decoder ValDecoder) ValDecoder
+	DecorateEncoder(typ reflect2.Type, encoder ValEncoder) ValEncoder
+}
+
+// RegisterFieldFormat register FieldFormat to be selected by format:<name> in struct tag
+func RegisterFieldFormat(name string, format FieldFormat) {
+	fieldFormats[name] = format
+}
+
+func init() {
+	RegisterFieldFormat("unix", &timeFormat{unit: time.Second})
+	RegisterFieldFormat("unixmilli", &timeFormat{unit: time.Millisecond})
+	RegisterFieldFormat("unixmicro", &timeFormat{unit: time.Microsecond})
+	RegisterFieldFormat("unixnano", &timeFormat{unit: time.Nanosecond})
+	RegisterFieldFormat("rfc3339", &timeFormat{layout: time.RFC3339Nano})
+	RegisterFieldFormat("hex", &bytesFormat{&hexBytesEncoding{}})
+	RegisterFieldFormat("base64", &bytesFormat{base64.StdEncoding})
+	RegisterFieldFormat("base64url", &bytesFormat{base64.URLEncoding})
+	RegisterFieldFormat("base64raw", &bytesFormat{base64.RawStdEncoding})
+	RegisterFieldFormat("base64rawurl", &bytesFormat{base64.RawURLEncoding})
+	RegisterFieldFormat("units", &durationUnitsFormat{})
+}
+
+// decorateWithFormat applies format:<name> to the field codecs, nextTagParts are the tag options after it.
+// time.Time fields also accept any Go time layout as format name, but not one with a comma,
+// as the tag options are separated by commas.
+func decorateWithFormat(typ reflect2.Type, name string, nextTagParts []string, decoder ValDecoder, encoder ValEncoder) (ValDecoder, ValEncoder) {
+	if len(nextTagParts) > 0 && !isTagOption(nextTagParts[0]) {
+		err := fmt.Errorf("format %s,%s: the format can not contain a comma", name, nextTagParts[0])
+		return &lazyErrorDecoder{err: err}, &lazyErrorEncoder{err: err}
+	}
+	format := fieldFormats[name]
+	if format == nil && (typ == timeType || typ == reflect2.PtrTo(timeType)) {
+		format = &timeFormat{layout: name}
+	}
+	if format == nil {
+		err := fmt.Errorf("unknown format %s", name)
+		return &lazyErrorDecoder{err: err}, &lazyErrorEncoder{err: err}
+	}
+	return decorateField(typ, decoder, encoder, format.DecorateDecoder, format.DecorateEncoder)
+}
+
+// isTagOption tells if tagPart looks like a tag option, a name maybe followed by = or :,
+// rather than the rest of a format cut at a comma
+func isTagOption(tagPart string) bool {
+	for i, c := range tagPart {
+		if (c == '=' || c == ':') && i > 0 {
+			return true
+		}
+		if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') {
+			return false
+		}
+	}
+	return tagPart != ""
+}
+
+// decorateWithPrecision applies precision=<digits> to float fields
+func decorateWithPrecision(typ reflect2.Type, digits string, decoder ValDecoder, encoder ValEncoder) (ValDecoder, ValEncoder) {
+	precision, err := strconv.Atoi(digits)
+	if err != nil || precision < 0 {
+		err := fmt.Errorf("invalid precision %s", digits)
+		return &lazyErrorDecoder{err: err}, &lazyErrorEncoder{err: err}
+	}
+	// decoding is not affected by precision
+	return decorateField(typ, decoder, encoder, nil, func(typ reflect2.Type, encoder ValEncoder) ValEncoder {
+		switch typ.Kind() {
+		case reflect.Float32:
+			return &precisionFloatEncoder{precision, 32}
+		case reflect.Float64:
+			return &precisionFloatEncoder{precision, 64}
+		}
+		return nil
+	})
+}
+
+// decorateField decorates the codecs of field, or the value pointed to if the field is a pointer.
+// Nil decorateDecoder keeps the decoder as is.
+func decorateField(typ reflect2.Type, decoder ValDecoder, encoder ValEncoder,
+	decorateDecoder func(reflect2.Type, ValDecoder) ValDecoder,
+	decorateEncoder func(reflect2.Type, ValEncoder) ValEncoder) (ValDecoder, ValEncoder) {
+	if typ.Kind() == reflect.Ptr {
+		// null is handled by the optional codec
+		elemType := typ.(*reflect2.UnsafePtrType).Elem()
+		var elemDecoder ValDecoder
+		if optionalDecoder, ok := decoder.(*OptionalDecoder); ok {
+			elemDecoder = optionalDecoder.ValueDecoder
+		}
+		var elemEncoder ValEncoder
+		if optionalEncoder, ok := encoder.(*OptionalEncoder); ok {
+			elemEncoder = optionalEncoder.ValueEncoder
+		}
+		elemDecoder, elemEncoder = decorateField(elemType, elemDecoder, elemEncoder, decorateDecoder, decorateEncoder)
+		if _, isErr := elemEncoder.(*lazyErrorEncoder); isErr {
+			return elemDecoder, elemEncoder
+		}
+		if decorateDecoder != nil {
+			decoder = &OptionalDecoder{elemType, elemDecoder}
+		}
+		return decoder, &OptionalEncoder{elemEncoder}
+	}
+	formatDecoder := decoder
+	if decorateDecoder != nil {
+		formatDecoder = decorateDecoder(typ, decoder)
+	}
+	formatEncoder := decorateEncoder(typ, encoder)
+	if formatEncoder == nil || (decorateDecoder != nil && formatDecoder == nil) {
+		err := fmt.Errorf("format does not support %s", typ.String())
+		return &lazyErrorDecoder{err: err}, &lazyErrorEncoder{err: err}
+	}
+	return formatDecoder, formatEncoder
+}
+
+type timeFormat struct {
+	unit   time.Duration
+	layout string
+}
+
+func (format *timeFormat) DecorateDecoder(typ reflect2.Type, decoder ValDecoder) ValDecoder {
+	if typ != timeType {
+		return nil
+	}
+	return &timeFormatCodec{format.unit, format.layout}
+}
+
+func (format *timeFormat) DecorateEncoder(typ reflect2.Type, encoder ValEncoder) ValEncoder {
+	if typ != timeType {
+		return nil
+	}
+	return &timeFormatCodec{format.unit, format.layout}
+}
+
+type timeFormatCodec struct {
+	unit   time.Duration
+	layout string
+}
+
+func (codec *timeFormatCodec) Decode(ptr unsafe.Pointer, iter *Iterator) {
+	if iter.ReadNil() {
+		return
+	}
+	if codec.layout == "" {
+		perSecond := int64(time.Second / codec.unit)
+		val := iter.ReadInt64()
+		*((*time.Time)(ptr)) = time.Unix(val/perSecond, val%perSecond*int64(codec.unit))
+		return
+	}
+	str := iter.ReadString()
+	val, err := time.Parse(codec.layout, str)
+	if err != nil {
+		iter.ReportError("decode time", err.Error())
+		return
+	}
+	*((*time.Time)(ptr)) = val
+}
+
+func (codec *timeFormatCodec) Encode(ptr unsafe.Pointer, stream *Stream) {
+	val := *((*time.Time)(ptr))
+	if codec.layout == "" {
+		perSecond := int64(time.Second / codec.unit)
+		stream.WriteInt64(val.Unix()*perSecond + int64(val.Nanosecond())/int64(codec.unit))
+		return
+	}
+	var buf [64]byte
+	stream.WriteString(string(val.AppendFormat(buf[:0], codec.layout)))
+}
+
+func (codec *timeFormatCodec) IsEmpty(ptr unsafe.Pointer) bool {
+	return (*((*time.Time)(ptr))).IsZero()
+}
+
+// bytesEncoding is satisfied by *base64.Encoding
+type bytesEncoding interface {
+	EncodedLen(n int) int
+	Encode(dst, src []byte)
+	DecodeString(s string) ([]byte, error)
+}
+
+type hexBytesEncoding struct {
+}
+
+func (encoding *hexBytesEncoding) EncodedLen(n int) int {
+	return hex.EncodedLen(n)
+}
+
+func (encoding *hexBytesEncoding) Encode(dst, src []byte) {
+	hex.Encode(dst, src)
+}
+
+func (encoding *hexBytesEncoding) DecodeString(s string) ([]byte, error) {
+	return hex.DecodeString(s)
+}
+
+type bytesFormat struct {
+	encoding bytesEncoding
+}
+
+func (format *bytesFormat) DecorateDecoder(typ reflect2.Type, decoder ValDecoder) ValDecoder {
+	if typ.Kind() != reflect.Slice || typ.(reflect2.SliceType).Elem().Kind() != reflect.Uint8 {
+		return nil
+	}
+	return &bytesFormatCodec{typ.(*reflect2.UnsafeSliceType), format.encoding}
+}
+
+func (format *bytesFormat) DecorateEncoder(typ reflect2.Type, encoder ValEncoder) ValEncoder {
+	if typ.Kind() != reflect.Slice || typ.(reflect2.SliceType).Elem().Kind() != reflect.Uint8 {
+		return nil
+	}
+	return &bytesFormatCodec{typ.(*reflect2.UnsafeSliceType), format.encoding}
+}
+
+type bytesFormatCodec struct {
+	sliceType *reflect2.UnsafeSliceType
+	encoding  bytesEncoding
+}
+
+func (codec *bytesFormatCodec) Decode(ptr unsafe.Pointer, iter *Iterator) {
+	if iter.ReadNil() {
+		codec.sliceType.UnsafeSetNil(ptr)
+		return
+	}
+	dst, err := codec.encoding.DecodeString(iter.ReadString())
+	if err != nil {
+		iter.ReportError("decode bytes", err.Error())
+		return
+	}
+	codec.sliceType.UnsafeSet(ptr, unsafe.Pointer(&dst))
+}
+
+func (codec *bytesFormatCodec) Encode(ptr unsafe.Pointer, stream *Stream) {
+	if codec.sliceType.UnsafeIsNil(ptr) {
+		stream.WriteNil()
+		return
+	}
+	src := *((*[]byte)(ptr))
+	stream.writeByte('"')
+	if len(src) != 0 {
+		size := codec.encoding.EncodedLen(len(src))
+		start := len(stream.buf)
+		for i := 0; i < size; i++ {
+			stream.buf = append(stream.buf, 0)
+		}
+		codec.encoding.Encode(stream.buf[start:], src)
+	}
+	stream.writeByte('"')
+}
+
+func (codec *bytesFormatCodec) IsEmpty(ptr unsafe.Pointer) bool {
+	return len(*((*[]byte)(ptr))) == 0
+}
+
+type durationUnitsFormat struct {
+}
+
+func (format *durationUnitsFormat) DecorateDecoder(typ reflect2.Type, decoder ValDecoder) ValDecoder {
+	if typ != durationType {
+		return nil
+	}
+	return &durationUnitsCodec{}
+}
+
+func (format *durationUnitsFormat) DecorateEncoder(typ reflect2.Type, encoder ValEncoder) ValEncoder {
+	if typ != durationType {
+		return nil
+	}
+	return &durationUnitsCodec{}
+}
+
+type durationUnitsCodec struct {
+}
+
+func (codec *durationUnitsCodec) Decode(ptr unsafe.Pointer, iter *Iterator) {
+	switch iter.WhatIsNext() {
+	case NilValue:
+		iter.skipFourBytes('n', 'u', 'l', 'l')
+	case NumberValue:
+		// plain number is read as nanoseconds, same as the default codec
+		*((*time.Duration)(ptr)) = time.Duration(iter.ReadInt64())
+	default:
+		val, err := time.ParseDuration(iter.ReadString())
+		if err != nil {
+			iter.ReportError("decode duration", err.Error())
+			return
+		}
+		*((*time.Duration)(ptr)) = val
+	}
+}
+
+func (codec *durationUnitsCodec) Encode(ptr unsafe.Pointer, stream *Stream) {
+	stream.WriteString((*((*time.Duration)(ptr))).String())
+}
+
+func (codec *durationUnitsCodec) IsEmpty(ptr unsafe.Pointer) bool {
+	return *((*time.Duration)(ptr)) == 0
+}
+
+type precisionFloatEncoder struct {
+	precision int
+	bitSize   int
+}
+
+func (encoder *precisionFloatEncoder) value(ptr unsafe.Pointer) float64 {
+	if encoder.bitSize == 32 {
+		return float64(*((*float32)(ptr)))
+	}
+	return *((*float64)(ptr))
+}
+
+func (encoder *precisionFloatEncoder) Encode(ptr unsafe.Pointer, stream *Stream) {
+	val := encoder.value(ptr)
+	if math.IsInf(val, 0) || math.IsNaN(val) {
+		if stream.cfg.invalidFloatToNil {
+			stream.WriteNil()
+			return
+		}
+		stream.Error = fmt.Errorf("unsupported value: %f", val)
+		return
+	}
+	stream.buf = strconv.AppendFloat(stream.buf, val, 'f', encoder.precision, encoder.bitSize)
+}
+
+func (encoder *precisionFloatEncoder) IsEmpty(ptr unsafe.Pointer) bool {
+	return encoder.value(ptr) == 0
+}
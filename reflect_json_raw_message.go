@@ -44,7 +44,7 @@ func (codec *jsonRawMessageCodec) Encode(ptr unsafe.Pointer, stream *Stream) {
 	if *((*json.RawMessage)(ptr)) == nil {
 		stream.WriteNil()
 	} else {
-		stream.WriteRaw(string(*((*json.RawMessage)(ptr))))
+		stream.writeRawValue(*((*json.RawMessage)(ptr)))
 	}
 }
 
@@ -67,7 +67,7 @@ func (codec *jsoniterRawMessageCodec) Encode(ptr unsafe.Pointer, stream *Stream)
 	if *((*RawMessage)(ptr)) == nil {
 		stream.WriteNil()
 	} else {
-		stream.WriteRaw(string(*((*RawMessage)(ptr))))
+		stream.writeRawValue(*((*RawMessage)(ptr)))
 	}
 }
 
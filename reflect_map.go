@@ -12,8 +12,17 @@ import (
 
 func decoderOfMap(ctx *ctx, typ reflect2.Type) ValDecoder {
 	mapType := typ.(*reflect2.UnsafeMapType)
-	keyDecoder := decoderOfMapKey(ctx.append("[mapKey]"), mapType.Key())
-	elemDecoder := decoderOfType(ctx.append("[mapElem]"), mapType.Elem())
+	if ctx.projection != nil && mapType.Key().Kind() == reflect.String {
+		return decoderOfProjectedMap(ctx, mapType)
+	}
+	// the maps not projected on their keys are decoded whole
+	keyCtx := ctx.append("[mapKey]")
+	keyCtx.projection = nil
+	keyDecoder := decoderOfMapKey(keyCtx, mapType.Key())
+	ctx.collectError(keyDecoder)
+	elemCtx := ctx.append("[mapElem]")
+	elemCtx.projection = nil
+	elemDecoder := decoderOfType(elemCtx, mapType.Elem())
 	return &mapDecoder{
 		mapType:     mapType,
 		keyType:     mapType.Key(),
@@ -25,16 +34,18 @@ func decoderOfMap(ctx *ctx, typ reflect2.Type) ValDecoder {
 
 func encoderOfMap(ctx *ctx, typ reflect2.Type) ValEncoder {
 	mapType := typ.(*reflect2.UnsafeMapType)
+	keyEncoder := encoderOfMapKey(ctx.append("[mapKey]"), mapType.Key())
+	ctx.collectError(keyEncoder)
 	if ctx.sortMapKeys {
 		return &sortKeysMapEncoder{
 			mapType:     mapType,
-			keyEncoder:  encoderOfMapKey(ctx.append("[mapKey]"), mapType.Key()),
+			keyEncoder:  keyEncoder,
 			elemEncoder: encoderOfType(ctx.append("[mapElem]"), mapType.Elem()),
 		}
 	}
 	return &mapEncoder{
 		mapType:     mapType,
-		keyEncoder:  encoderOfMapKey(ctx.append("[mapKey]"), mapType.Key()),
+		keyEncoder:  keyEncoder,
 		elemEncoder: encoderOfType(ctx.append("[mapElem]"), mapType.Elem()),
 	}
 }
@@ -198,6 +209,58 @@ func (decoder *mapDecoder) Decode(ptr unsafe.Pointer, iter *Iterator) {
 	}
 }
 
+func decoderOfProjectedMap(ctx *ctx, mapType *reflect2.UnsafeMapType) ValDecoder {
+	elemCtx := ctx.append("[mapElem]")
+	elemCtx.projection = nil
+	elemDecoders := map[string]ValDecoder{}
+	for key, child := range ctx.projection.children {
+		if child.children != nil {
+			childCtx := ctx.append("[mapElem]")
+			childCtx.projection = child
+			elemDecoders[key] = decoderOfType(childCtx, mapType.Elem())
+		}
+	}
+	return &projectedMapDecoder{
+		mapType:      mapType,
+		projection:   ctx.projection,
+		elemDecoder:  decoderOfType(elemCtx, mapType.Elem()),
+		elemDecoders: elemDecoders,
+	}
+}
+
+type projectedMapDecoder struct {
+	mapType      *reflect2.UnsafeMapType
+	projection   *FieldMask
+	elemDecoder  ValDecoder
+	elemDecoders map[string]ValDecoder
+}
+
+func (decoder *projectedMapDecoder) Decode(ptr unsafe.Pointer, iter *Iterator) {
+	mapType := decoder.mapType
+	if iter.ReadNil() {
+		*(*unsafe.Pointer)(ptr) = nil
+		mapType.UnsafeSet(ptr, mapType.UnsafeNew())
+		return
+	}
+	if mapType.UnsafeIsNil(ptr) {
+		mapType.UnsafeSet(ptr, mapType.UnsafeMakeMap(0))
+	}
+	iter.ReadMapCB(func(iter *Iterator, key string) bool {
+		if _, wanted := decoder.projection.field(key); !wanted {
+			iter.Skip()
+			return true
+		}
+		elemDecoder := decoder.elemDecoders[key]
+		if elemDecoder == nil {
+			elemDecoder = decoder.elemDecoder
+		}
+		elem := mapType.Elem().UnsafeNew()
+		elemDecoder.Decode(elem, iter)
+		mapType.UnsafeSetIndex(ptr, unsafe.Pointer(&key), elem)
+		return true
+	})
+}
+
 type numericMapKeyDecoder struct {
 	decoder ValDecoder
 }
@@ -256,6 +319,10 @@ func (encoder *mapEncoder) Encode(ptr unsafe.Pointer, stream *Stream) {
 		stream.WriteNil()
 		return
 	}
+	if stream.fieldMask != nil {
+		encoder.encodeMasked(ptr, stream)
+		return
+	}
 	stream.WriteObjectStart()
 	iter := encoder.mapType.UnsafeIterate(ptr)
 	for i := 0; iter.HasNext(); i++ {
@@ -274,6 +341,44 @@ func (encoder *mapEncoder) Encode(ptr unsafe.Pointer, stream *Stream) {
 	stream.WriteObjectEnd()
 }
 
+func (encoder *mapEncoder) encodeMasked(ptr unsafe.Pointer, stream *Stream) {
+	mask := stream.fieldMask
+	stream.WriteObjectStart()
+	mapIter := encoder.mapType.UnsafeIterate(ptr)
+	subStream := stream.borrowSubStream()
+	subIter := stream.cfg.BorrowIterator(nil)
+	isNotFirst := false
+	for mapIter.HasNext() {
+		key, elem := mapIter.UnsafeNext()
+		subStream.Reset(nil)
+		encoder.keyEncoder.Encode(key, subStream)
+		if subStream.Error != nil && subStream.Error != io.EOF && stream.Error == nil {
+			stream.Error = subStream.Error
+		}
+		subIter.ResetBytes(subStream.Buffer())
+		childMask, visible := mask.field(subIter.ReadString())
+		if !visible {
+			continue
+		}
+		if isNotFirst {
+			stream.WriteMore()
+		}
+		stream.Write(subStream.Buffer())
+		if stream.indention > 0 {
+			stream.writeTwoBytes(byte(':'), byte(' '))
+		} else {
+			stream.writeByte(':')
+		}
+		stream.fieldMask = childMask
+		encoder.elemEncoder.Encode(elem, stream)
+		stream.fieldMask = mask
+		isNotFirst = true
+	}
+	stream.WriteObjectEnd()
+	stream.cfg.ReturnStream(subStream)
+	stream.cfg.ReturnIterator(subIter)
+}
+
 func (encoder *mapEncoder) IsEmpty(ptr unsafe.Pointer) bool {
 	iter := encoder.mapType.UnsafeIterate(ptr)
 	return !iter.HasNext()
@@ -292,8 +397,7 @@ func (encoder *sortKeysMapEncoder) Encode(ptr unsafe.Pointer, stream *Stream) {
 	}
 	stream.WriteObjectStart()
 	mapIter := encoder.mapType.UnsafeIterate(ptr)
-	subStream := stream.cfg.BorrowStream(nil)
-	subStream.Attachment = stream.Attachment
+	subStream := stream.borrowSubStream()
 	subIter := stream.cfg.BorrowIterator(nil)
 	keyValues := encodedKeyValues{}
 	for mapIter.HasNext() {
@@ -306,6 +410,14 @@ func (encoder *sortKeysMapEncoder) Encode(ptr unsafe.Pointer, stream *Stream) {
 		encodedKey := subStream.Buffer()[subStreamIndex:]
 		subIter.ResetBytes(encodedKey)
 		decodedKey := subIter.ReadString()
+		if stream.fieldMask != nil {
+			childMask, visible := stream.fieldMask.field(decodedKey)
+			if !visible {
+				subStream.buf = subStream.buf[:subStreamIndex]
+				continue
+			}
+			subStream.fieldMask = childMask
+		}
 		if stream.indention > 0 {
 			subStream.writeTwoBytes(byte(':'), byte(' '))
 		} else {
@@ -3,6 +3,8 @@ package jsoniter
 import (
 	"encoding"
 	"encoding/json"
+	"fmt"
+	"reflect"
 	"unsafe"
 
 	"github.com/modern-go/reflect2"
@@ -12,9 +14,26 @@ var marshalerType = reflect2.TypeOfPtr((*json.Marshaler)(nil)).Elem()
 var unmarshalerType = reflect2.TypeOfPtr((*json.Unmarshaler)(nil)).Elem()
 var textMarshalerType = reflect2.TypeOfPtr((*encoding.TextMarshaler)(nil)).Elem()
 var textUnmarshalerType = reflect2.TypeOfPtr((*encoding.TextUnmarshaler)(nil)).Elem()
+var streamMarshalerType = reflect2.TypeOfPtr((*StreamMarshaler)(nil)).Elem()
+var iteratorUnmarshalerType = reflect2.TypeOfPtr((*IteratorUnmarshaler)(nil)).Elem()
+
+// StreamMarshaler is implemented by types writing their JSON directly into the stream.
+// It is preferred over json.Marshaler when a type implements both.
+type StreamMarshaler interface {
+	MarshalJSONTo(stream *Stream) error
+}
+
+// IteratorUnmarshaler is implemented by types reading their JSON directly from the iterator.
+// It is preferred over json.Unmarshaler when a type implements both.
+type IteratorUnmarshaler interface {
+	UnmarshalJSONFrom(iter *Iterator) error
+}
 
 func createDecoderOfMarshaler(ctx *ctx, typ reflect2.Type) ValDecoder {
 	ptrType := reflect2.PtrTo(typ)
+	if ptrType.Implements(iteratorUnmarshalerType) {
+		return &iteratorUnmarshalerDecoder{typ}
+	}
 	if ptrType.Implements(unmarshalerType) {
 		return &referenceDecoder{
 			&unmarshalerDecoder{ptrType},
@@ -29,6 +48,24 @@ func createDecoderOfMarshaler(ctx *ctx, typ reflect2.Type) ValDecoder {
 }
 
 func createEncoderOfMarshaler(ctx *ctx, typ reflect2.Type) ValEncoder {
+	if typ.Kind() != reflect.Interface && typ.Implements(streamMarshalerType) {
+		checkIsEmpty := createCheckIsEmpty(ctx, typ)
+		var encoder ValEncoder = &streamMarshalerEncoder{
+			valType:      typ,
+			checkIsEmpty: checkIsEmpty,
+		}
+		return encoder
+	}
+	ptrType := reflect2.PtrTo(typ)
+	if ctx.prefix != "" && ptrType.Implements(streamMarshalerType) {
+		checkIsEmpty := createCheckIsEmpty(ctx, typ)
+		var encoder ValEncoder = &streamMarshalerEncoder{
+			valType:      typ,
+			checkIsEmpty: checkIsEmpty,
+			byPointer:    true,
+		}
+		return encoder
+	}
 	if typ == marshalerType {
 		checkIsEmpty := createCheckIsEmpty(ctx, typ)
 		var encoder ValEncoder = &directMarshalerEncoder{
@@ -44,7 +81,6 @@ func createEncoderOfMarshaler(ctx *ctx, typ reflect2.Type) ValEncoder {
 		}
 		return encoder
 	}
-	ptrType := reflect2.PtrTo(typ)
 	if ctx.prefix != "" && ptrType.Implements(marshalerType) {
 		checkIsEmpty := createCheckIsEmpty(ctx, ptrType)
 		var encoder ValEncoder = &marshalerEncoder{
@@ -83,6 +119,34 @@ func createEncoderOfMarshaler(ctx *ctx, typ reflect2.Type) ValEncoder {
 	return nil
 }
 
+type streamMarshalerEncoder struct {
+	checkIsEmpty checkIsEmpty
+	valType      reflect2.Type
+	byPointer    bool // the pointer to the value implements StreamMarshaler
+}
+
+func (encoder *streamMarshalerEncoder) Encode(ptr unsafe.Pointer, stream *Stream) {
+	var obj interface{}
+	if encoder.byPointer {
+		// packing ptr itself, taking its address would allocate
+		obj = encoder.valType.PackEFace(ptr)
+	} else {
+		obj = encoder.valType.UnsafeIndirect(ptr)
+		if encoder.valType.IsNullable() && reflect2.IsNil(obj) {
+			stream.WriteNil()
+			return
+		}
+	}
+	err := obj.(StreamMarshaler).MarshalJSONTo(stream)
+	if err != nil && stream.Error == nil {
+		stream.Error = err
+	}
+}
+
+func (encoder *streamMarshalerEncoder) IsEmpty(ptr unsafe.Pointer) bool {
+	return encoder.checkIsEmpty.IsEmpty(ptr)
+}
+
 type marshalerEncoder struct {
 	checkIsEmpty checkIsEmpty
 	valType      reflect2.Type
@@ -99,13 +163,7 @@ func (encoder *marshalerEncoder) Encode(ptr unsafe.Pointer, stream *Stream) {
 	if err != nil {
 		stream.Error = err
 	} else {
-		// html escape was already done by jsoniter
-		// but the extra '\n' should be trimed
-		l := len(bytes)
-		if l > 0 && bytes[l-1] == '\n' {
-			bytes = bytes[:l-1]
-		}
-		stream.Write(bytes)
+		writeMarshalerOutput(stream, encoder.valType.String(), bytes)
 	}
 }
 
@@ -127,8 +185,21 @@ func (encoder *directMarshalerEncoder) Encode(ptr unsafe.Pointer, stream *Stream
 	if err != nil {
 		stream.Error = err
 	} else {
-		stream.Write(bytes)
+		writeMarshalerOutput(stream, reflect2.TypeOf(marshaler).String(), bytes)
+	}
+}
+
+// writeMarshalerOutput writes the MarshalJSON output compacted, or indented as the stream is
+func writeMarshalerOutput(stream *Stream, typeName string, bytes []byte) {
+	if stream.cfg.validateMarshalerOutput {
+		if err := stream.cfg.validValue(bytes); err != nil {
+			if stream.Error == nil {
+				stream.Error = fmt.Errorf("error calling MarshalJSON for type %s: %s", typeName, err.Error())
+			}
+			return
+		}
 	}
+	stream.writeRawValue(bytes)
 }
 
 func (encoder *directMarshalerEncoder) IsEmpty(ptr unsafe.Pointer) bool {
@@ -202,6 +273,18 @@ func (decoder *unmarshalerDecoder) Decode(ptr unsafe.Pointer, iter *Iterator) {
 	}
 }
 
+type iteratorUnmarshalerDecoder struct {
+	valType reflect2.Type
+}
+
+func (decoder *iteratorUnmarshalerDecoder) Decode(ptr unsafe.Pointer, iter *Iterator) {
+	unmarshaler := decoder.valType.PackEFace(ptr).(IteratorUnmarshaler)
+	err := unmarshaler.UnmarshalJSONFrom(iter)
+	if err != nil && iter.Error == nil {
+		iter.ReportError("iteratorUnmarshalerDecoder", err.Error())
+	}
+}
+
 type textUnmarshalerDecoder struct {
 	valType reflect2.Type
 }
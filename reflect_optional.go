@@ -2,6 +2,7 @@ package jsoniter
 
 import (
 	"github.com/modern-go/reflect2"
+	"reflect"
 	"unsafe"
 )
 
@@ -127,3 +128,88 @@ type referenceDecoder struct {
 func (decoder *referenceDecoder) Decode(ptr unsafe.Pointer, iter *Iterator) {
 	decoder.decoder.Decode(unsafe.Pointer(&ptr), iter)
 }
+
+// optionalValue is implemented by the pointer of Nullable and Optional,
+// which keep track of the presence of the value in a state field.
+type optionalValue interface {
+	// nullIsPresent tells if null is kept apart from absent
+	nullIsPresent() bool
+}
+
+var optionalValueType = reflect2.TypeOfPtr((*optionalValue)(nil)).Elem()
+
+const (
+	valueAbsent uint8 = iota
+	valueNull
+	valueSet
+)
+
+func createDecoderOfOptionalValue(ctx *ctx, typ reflect2.Type) ValDecoder {
+	if typ.Kind() != reflect.Struct || !reflect2.PtrTo(typ).Implements(optionalValueType) {
+		return nil
+	}
+	structType := typ.(*reflect2.UnsafeStructType)
+	valueField := structType.FieldByName("value")
+	return &optionalValueDecoder{
+		stateField:    structType.FieldByName("state"),
+		valueField:    valueField,
+		valueDecoder:  decoderOfType(ctx, valueField.Type()),
+		nullIsPresent: typ.New().(optionalValue).nullIsPresent(),
+	}
+}
+
+func createEncoderOfOptionalValue(ctx *ctx, typ reflect2.Type) ValEncoder {
+	if typ.Kind() != reflect.Struct || !reflect2.PtrTo(typ).Implements(optionalValueType) {
+		return nil
+	}
+	structType := typ.(*reflect2.UnsafeStructType)
+	valueField := structType.FieldByName("value")
+	return &optionalValueEncoder{
+		stateField:   structType.FieldByName("state"),
+		valueField:   valueField,
+		valueEncoder: encoderOfType(ctx, valueField.Type()),
+	}
+}
+
+type optionalValueDecoder struct {
+	stateField    reflect2.StructField
+	valueField    reflect2.StructField
+	valueDecoder  ValDecoder
+	nullIsPresent bool
+}
+
+func (decoder *optionalValueDecoder) Decode(ptr unsafe.Pointer, iter *Iterator) {
+	state := (*uint8)(decoder.stateField.UnsafeGet(ptr))
+	valuePtr := decoder.valueField.UnsafeGet(ptr)
+	if iter.ReadNil() {
+		valueType := decoder.valueField.Type()
+		valueType.UnsafeSet(valuePtr, valueType.UnsafeNew())
+		if decoder.nullIsPresent {
+			*state = valueNull
+		} else {
+			*state = valueAbsent
+		}
+		return
+	}
+	decoder.valueDecoder.Decode(valuePtr, iter)
+	*state = valueSet
+}
+
+type optionalValueEncoder struct {
+	stateField   reflect2.StructField
+	valueField   reflect2.StructField
+	valueEncoder ValEncoder
+}
+
+func (encoder *optionalValueEncoder) Encode(ptr unsafe.Pointer, stream *Stream) {
+	if *((*uint8)(encoder.stateField.UnsafeGet(ptr))) != valueSet {
+		stream.WriteNil()
+		return
+	}
+	encoder.valueEncoder.Encode(encoder.valueField.UnsafeGet(ptr), stream)
+}
+
+// IsEmpty only if absent, struct fields of this kind are always omitted when empty
+func (encoder *optionalValueEncoder) IsEmpty(ptr unsafe.Pointer) bool {
+	return *((*uint8)(encoder.stateField.UnsafeGet(ptr))) == valueAbsent
+}
@@ -0,0 +1,79 @@
+package jsoniter
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"unsafe"
+)
+
+// RedactionPolicy tells how fields tagged with the redact option are written
+type RedactionPolicy int
+
+const (
+	// RedactNone writes the value as is
+	RedactNone RedactionPolicy = iota
+	// RedactOmit skips the field
+	RedactOmit
+	// RedactMask writes "***" in place of the value
+	RedactMask
+	// RedactHash writes the hex encoded HMAC-SHA256 of the value as JSON, keyed by Config.RedactionKey.
+	// Without a key it is a plain sha256: short or guessable values are recovered with a dictionary,
+	// the hash only correlates equal values and is not a privacy control.
+	RedactHash
+)
+
+// SetRedaction overrides the redaction policy of the config for this stream
+func (stream *Stream) SetRedaction(policy RedactionPolicy) {
+	stream.redaction = policy
+}
+
+type redactEncoder struct {
+	encoder ValEncoder
+}
+
+func (encoder *redactEncoder) Encode(ptr unsafe.Pointer, stream *Stream) {
+	switch stream.redaction {
+	case RedactNone:
+		encoder.encoder.Encode(ptr, stream)
+	case RedactHash:
+		tempStream := stream.borrowSubStream()
+		defer stream.cfg.ReturnStream(tempStream)
+		encoder.encoder.Encode(ptr, tempStream)
+		if tempStream.Error != nil {
+			stream.Error = tempStream.Error
+			return
+		}
+		var digest hash.Hash
+		if stream.cfg.redactionKey != nil {
+			digest = hmac.New(sha256.New, stream.cfg.redactionKey)
+		} else {
+			digest = sha256.New()
+		}
+		digest.Write(tempStream.Buffer())
+		stream.WriteString(hex.EncodeToString(digest.Sum(nil)))
+	default:
+		stream.WriteString("***")
+	}
+}
+
+func (encoder *redactEncoder) IsEmpty(ptr unsafe.Pointer) bool {
+	return encoder.encoder.IsEmpty(ptr)
+}
+
+// isRedacted tells if the struct field is tagged with the redact option, possibly through embedded structs
+func isRedacted(encoder ValEncoder) bool {
+	for {
+		switch typedEncoder := encoder.(type) {
+		case *structFieldEncoder:
+			encoder = typedEncoder.fieldEncoder
+		case *dereferenceEncoder:
+			encoder = typedEncoder.ValueEncoder
+		case *redactEncoder:
+			return true
+		default:
+			return false
+		}
+	}
+}
@@ -8,14 +8,34 @@ import (
 	"unsafe"
 )
 
+type bindingTo struct {
+	binding *Binding
+	toName  string
+	ignored bool
+}
+
 func encoderOfStruct(ctx *ctx, typ reflect2.Type) ValEncoder {
-	type bindingTo struct {
-		binding *Binding
-		toName  string
-		ignored bool
+	structDescriptor := describeStruct(ctx, typ)
+	orderedBindings := encodingBindings(ctx, structDescriptor)
+	if len(orderedBindings) == 0 {
+		return &emptyStructEncoder{}
+	}
+	finalOrderedFields := []structFieldTo{}
+	for _, bindingTo := range orderedBindings {
+		if !bindingTo.ignored {
+			finalOrderedFields = append(finalOrderedFields, structFieldTo{
+				encoder: bindingTo.binding.Encoder.(*structFieldEncoder),
+				toName:  bindingTo.toName,
+				redact:  isRedacted(bindingTo.binding.Encoder),
+			})
+		}
 	}
+	return &structEncoder{typ, finalOrderedFields}
+}
+
+// encodingBindings lists the names encoded in order, ignored when lost to another binding
+func encodingBindings(ctx *ctx, structDescriptor *StructDescriptor) []*bindingTo {
 	orderedBindings := []*bindingTo{}
-	structDescriptor := describeStruct(ctx, typ)
 	for _, binding := range structDescriptor.Fields {
 		for _, toName := range binding.ToNames {
 			new := &bindingTo{
@@ -31,19 +51,7 @@ func encoderOfStruct(ctx *ctx, typ reflect2.Type) ValEncoder {
 			orderedBindings = append(orderedBindings, new)
 		}
 	}
-	if len(orderedBindings) == 0 {
-		return &emptyStructEncoder{}
-	}
-	finalOrderedFields := []structFieldTo{}
-	for _, bindingTo := range orderedBindings {
-		if !bindingTo.ignored {
-			finalOrderedFields = append(finalOrderedFields, structFieldTo{
-				encoder: bindingTo.binding.Encoder.(*structFieldEncoder),
-				toName:  bindingTo.toName,
-			})
-		}
-	}
-	return &structEncoder{typ, finalOrderedFields}
+	return orderedBindings
 }
 
 func createCheckIsEmpty(ctx *ctx, typ reflect2.Type) checkIsEmpty {
@@ -139,9 +147,11 @@ type structEncoder struct {
 type structFieldTo struct {
 	encoder *structFieldEncoder
 	toName  string
+	redact  bool
 }
 
 func (encoder *structEncoder) Encode(ptr unsafe.Pointer, stream *Stream) {
+	mask := stream.fieldMask
 	stream.WriteObjectStart()
 	isNotFirst := false
 	for _, field := range encoder.fields {
@@ -151,6 +161,16 @@ func (encoder *structEncoder) Encode(ptr unsafe.Pointer, stream *Stream) {
 		if field.encoder.IsEmbeddedPtrNil(ptr) {
 			continue
 		}
+		if field.redact && stream.redaction == RedactOmit {
+			continue
+		}
+		if mask != nil {
+			childMask, visible := mask.field(field.toName)
+			if !visible {
+				continue
+			}
+			stream.fieldMask = childMask
+		}
 		if isNotFirst {
 			stream.WriteMore()
 		}
@@ -158,6 +178,7 @@ func (encoder *structEncoder) Encode(ptr unsafe.Pointer, stream *Stream) {
 		field.encoder.Encode(ptr, stream)
 		isNotFirst = true
 	}
+	stream.fieldMask = mask
 	stream.WriteObjectEnd()
 	if stream.Error != nil && stream.Error != io.EOF {
 		stream.Error = fmt.Errorf("%v.%s", encoder.typ, stream.Error.Error())
@@ -199,9 +220,8 @@ type stringModeStringEncoder struct {
 }
 
 func (encoder *stringModeStringEncoder) Encode(ptr unsafe.Pointer, stream *Stream) {
-	tempStream := encoder.cfg.BorrowStream(nil)
-	tempStream.Attachment = stream.Attachment
-	defer encoder.cfg.ReturnStream(tempStream)
+	tempStream := stream.borrowSubStream()
+	defer stream.cfg.ReturnStream(tempStream)
 	encoder.elemEncoder.Encode(ptr, tempStream)
 	stream.WriteString(string(tempStream.Buffer()))
 }
@@ -0,0 +1,178 @@
+package jsoniter
+
+import (
+	"time"
+	"unsafe"
+
+	"github.com/modern-go/reflect2"
+)
+
+func createDecoderOfTime(ctx *ctx, typ reflect2.Type) ValDecoder {
+	if typ == timeType {
+		return newTimeCodec()
+	}
+	if typ == reflect2.PtrTo(timeType) {
+		return &OptionalDecoder{timeType, newTimeCodec()}
+	}
+	return nil
+}
+
+func createEncoderOfTime(ctx *ctx, typ reflect2.Type) ValEncoder {
+	if typ == timeType {
+		return newTimeCodec()
+	}
+	if typ == reflect2.PtrTo(timeType) {
+		return &OptionalEncoder{newTimeCodec()}
+	}
+	return nil
+}
+
+func newTimeCodec() *timeCodec {
+	return &timeCodec{
+		fallbackDecoder: &referenceDecoder{&unmarshalerDecoder{reflect2.PtrTo(timeType)}},
+	}
+}
+
+// timeCodec encodes and decodes time.Time same as its MarshalJSON and UnmarshalJSON, without allocation.
+// Anything out of the fast path is handed to time.Time itself, so the output and errors stay the same.
+type timeCodec struct {
+	fallbackDecoder ValDecoder
+}
+
+func (codec *timeCodec) Decode(ptr unsafe.Pointer, iter *Iterator) {
+	c := iter.nextToken()
+	if c == '"' {
+		for i := iter.head; i < iter.tail; i++ {
+			b := iter.buf[i]
+			if b == '"' {
+				val, ok := parseTimeRFC3339(iter.buf[iter.head:i])
+				if ok {
+					*((*time.Time)(ptr)) = val
+					iter.head = i + 1
+					return
+				}
+				break
+			}
+			if b == '\\' {
+				break
+			}
+		}
+	}
+	iter.unreadByte()
+	codec.fallbackDecoder.Decode(ptr, iter)
+}
+
+func (codec *timeCodec) Encode(ptr unsafe.Pointer, stream *Stream) {
+	val := *((*time.Time)(ptr))
+	if !canWriteTime(val) {
+		_, err := val.MarshalJSON()
+		stream.Error = err
+		return
+	}
+	stream.WriteTime(val)
+}
+
+func (codec *timeCodec) IsEmpty(ptr unsafe.Pointer) bool {
+	// same as any other struct, never empty
+	return false
+}
+
+// canWriteTime checks the edge cases Time.MarshalJSON refuses, see golang.org/issue/54580
+func canWriteTime(val time.Time) bool {
+	if y := val.Year(); y < 0 || y >= 10000 {
+		return false
+	}
+	_, offset := val.Zone()
+	if offset < 0 {
+		offset = -offset
+	}
+	return offset < 24*60*60
+}
+
+// parseTimeRFC3339 mimics the fast path of Time.UnmarshalJSON.
+// Returns false if the input is not understood, the caller should fall back to the standard library.
+func parseTimeRFC3339(s []byte) (time.Time, bool) {
+	if len(s) < len("2006-01-02T15:04:05") {
+		return time.Time{}, false
+	}
+	if s[4] != '-' || s[7] != '-' || s[10] != 'T' || s[13] != ':' || s[16] != ':' {
+		return time.Time{}, false
+	}
+	year, ok1 := parseTimeDigits(s[0:4], 0, 9999)
+	month, ok2 := parseTimeDigits(s[5:7], 1, 12)
+	if !ok1 || !ok2 {
+		return time.Time{}, false
+	}
+	day, ok3 := parseTimeDigits(s[8:10], 1, daysIn(month, year))
+	hour, ok4 := parseTimeDigits(s[11:13], 0, 23)
+	min, ok5 := parseTimeDigits(s[14:16], 0, 59)
+	sec, ok6 := parseTimeDigits(s[17:19], 0, 59)
+	if !ok3 || !ok4 || !ok5 || !ok6 {
+		return time.Time{}, false
+	}
+	s = s[19:]
+	nsec := 0
+	if len(s) >= 2 && s[0] == '.' && isTimeDigit(s[1]) {
+		n := 1
+		for ; n < len(s) && isTimeDigit(s[n]); n++ {
+			if n <= 9 {
+				nsec = nsec*10 + int(s[n]-'0')
+			}
+		}
+		for scale := n; scale <= 9; scale++ {
+			nsec *= 10
+		}
+		s = s[n:]
+	}
+	val := time.Date(year, time.Month(month), day, hour, min, sec, nsec, time.UTC)
+	if len(s) == 1 && s[0] == 'Z' {
+		return val, true
+	}
+	if len(s) != len("-07:00") || (s[0] != '-' && s[0] != '+') || s[3] != ':' {
+		return time.Time{}, false
+	}
+	zoneHour, ok1 := parseTimeDigits(s[1:3], 0, 23)
+	zoneMin, ok2 := parseTimeDigits(s[4:6], 0, 59)
+	if !ok1 || !ok2 {
+		return time.Time{}, false
+	}
+	zoneOffset := (zoneHour*60 + zoneMin) * 60
+	if s[0] == '-' {
+		zoneOffset = -zoneOffset
+	}
+	val = val.Add(-time.Duration(zoneOffset) * time.Second)
+	// use local zone with the given offset if possible
+	local := val.In(time.Local)
+	if _, offset := local.Zone(); offset == zoneOffset {
+		return local, true
+	}
+	return val.In(time.FixedZone("", zoneOffset)), true
+}
+
+func parseTimeDigits(s []byte, min, max int) (int, bool) {
+	val := 0
+	for _, c := range s {
+		if !isTimeDigit(c) {
+			return 0, false
+		}
+		val = val*10 + int(c-'0')
+	}
+	return val, min <= val && val <= max
+}
+
+func isTimeDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func daysIn(month int, year int) int {
+	switch month {
+	case 2:
+		if year%4 == 0 && (year%100 != 0 || year%400 == 0) {
+			return 29
+		}
+		return 28
+	case 4, 6, 9, 11:
+		return 30
+	}
+	return 31
+}
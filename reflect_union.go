@@ -0,0 +1,213 @@
+package jsoniter
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"unsafe"
+
+	"github.com/modern-go/reflect2"
+)
+
+var unions = map[reflect2.Type]*unionDescriptor{}
+
+type unionDescriptor struct {
+	discriminator string
+	variants      map[string]reflect2.Type
+	tags          map[uintptr]string
+}
+
+// RegisterUnion register the concrete types of an interface, selected by the value of discriminator field.
+// iface is a pointer to the interface, for example (*Shape)(nil).
+// variants maps the discriminator value to a sample of the concrete type, for example "circle": Circle{} or &Circle{}.
+// When decoding, the discriminator can appear anywhere in the object, and is also seen by the concrete type.
+// When encoding, the discriminator is written as the first field, unless the concrete type has a field of the same name.
+func RegisterUnion(iface interface{}, discriminator string, variants map[string]interface{}) {
+	ifaceType := reflect2.TypeOfPtr(iface).Elem()
+	if ifaceType.Kind() != reflect.Interface {
+		panic(fmt.Sprintf("%s is not interface", ifaceType.String()))
+	}
+	union := &unionDescriptor{
+		discriminator: discriminator,
+		variants:      map[string]reflect2.Type{},
+		tags:          map[uintptr]string{},
+	}
+	for tag, variant := range variants {
+		variantType := reflect2.TypeOf(variant)
+		if !variantType.Type1().Implements(ifaceType.Type1()) {
+			panic(fmt.Sprintf("%s does not implement %s", variantType.String(), ifaceType.String()))
+		}
+		union.variants[tag] = variantType
+		union.tags[variantType.RType()] = tag
+	}
+	unions[ifaceType] = union
+}
+
+func createDecoderOfUnion(ctx *ctx, typ reflect2.Type) ValDecoder {
+	union := unions[typ]
+	if union == nil {
+		return nil
+	}
+	return &unionDecoder{typ, union}
+}
+
+func createEncoderOfUnion(ctx *ctx, typ reflect2.Type) ValEncoder {
+	union := unions[typ]
+	if union == nil {
+		return nil
+	}
+	hasDiscriminator := map[uintptr]bool{}
+	for _, variantType := range union.variants {
+		hasDiscriminator[variantType.RType()] = hasField(ctx, variantType, union.discriminator)
+	}
+	return &unionEncoder{typ, union, hasDiscriminator}
+}
+
+func hasField(ctx *ctx, typ reflect2.Type, name string) bool {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.(*reflect2.UnsafePtrType).Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return false
+	}
+	for _, binding := range describeStruct(ctx, typ).Fields {
+		for _, toName := range binding.ToNames {
+			if toName == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type unionDecoder struct {
+	valType reflect2.Type
+	union   *unionDescriptor
+}
+
+func (decoder *unionDecoder) Decode(ptr unsafe.Pointer, iter *Iterator) {
+	if iter.ReadNil() {
+		decoder.valType.UnsafeSet(ptr, decoder.valType.UnsafeNew())
+		return
+	}
+	if iter.reader != nil {
+		// the object might not fit in the buffer, read it whole before looking for the discriminator
+		data := iter.SkipAndReturnBytes()
+		if iter.Error != nil && iter.Error != io.EOF {
+			return
+		}
+		tempIter := iter.Pool().BorrowIterator(data)
+		defer iter.Pool().ReturnIterator(tempIter)
+		decoder.decodeBuffered(ptr, tempIter)
+		if tempIter.Error != nil && tempIter.Error != io.EOF {
+			iter.Error = tempIter.Error
+		}
+		return
+	}
+	decoder.decodeBuffered(ptr, iter)
+}
+
+func (decoder *unionDecoder) decodeBuffered(ptr unsafe.Pointer, iter *Iterator) {
+	c := iter.nextToken()
+	if c != '{' {
+		iter.ReportError("union Decode", `expect {, but found `+string([]byte{c}))
+		return
+	}
+	iter.unreadByte()
+	start := iter.head
+	var variantType reflect2.Type
+	for field := iter.ReadObjectRaw(); !field.IsNil(); field = iter.ReadObjectRaw() {
+		if !field.hasEscapes && string(field.buf[:len(field.buf)-1]) != decoder.union.discriminator ||
+			field.hasEscapes && field.String() != decoder.union.discriminator {
+			iter.Skip()
+			continue
+		}
+		tag := iter.ReadRawString()
+		if iter.Error != nil {
+			return
+		}
+		tagBytes, _ := tag.Bytes()
+		variantType = decoder.union.variants[string(tagBytes)]
+		if variantType == nil {
+			iter.ReportError("union Decode", "unknown "+decoder.union.discriminator+" "+tag.String())
+			return
+		}
+		break
+	}
+	if iter.Error != nil {
+		return
+	}
+	if variantType == nil {
+		iter.ReportError("union Decode", "missing "+decoder.union.discriminator)
+		return
+	}
+	// decode the whole object again, the concrete type sees the discriminator like any other field
+	iter.head = start
+	var val reflect.Value
+	if variantType.Kind() == reflect.Ptr {
+		obj := variantType.(*reflect2.UnsafePtrType).Elem().New()
+		iter.ReadVal(obj)
+		val = reflect.ValueOf(obj)
+	} else {
+		obj := variantType.New()
+		iter.ReadVal(obj)
+		val = reflect.ValueOf(obj).Elem()
+	}
+	reflect.NewAt(decoder.valType.Type1(), ptr).Elem().Set(val)
+}
+
+type unionEncoder struct {
+	valType          reflect2.Type
+	union            *unionDescriptor
+	hasDiscriminator map[uintptr]bool
+}
+
+func (encoder *unionEncoder) Encode(ptr unsafe.Pointer, stream *Stream) {
+	obj := encoder.valType.UnsafeIndirect(ptr)
+	if obj == nil {
+		stream.WriteNil()
+		return
+	}
+	rtype := reflect2.RTypeOf(obj)
+	tag, found := encoder.union.tags[rtype]
+	if !found || encoder.hasDiscriminator[rtype] || !encoder.discriminatorVisible(stream) {
+		stream.WriteVal(obj)
+		return
+	}
+	tempStream := stream.borrowSubStream()
+	defer stream.cfg.ReturnStream(tempStream)
+	tempStream.WriteVal(obj)
+	if tempStream.Error != nil {
+		stream.Error = tempStream.Error
+		return
+	}
+	encoded := tempStream.Buffer()
+	if len(encoded) == 0 || encoded[0] != '{' {
+		// not an object, nowhere to put the discriminator
+		stream.buf = append(stream.buf, encoded...)
+		return
+	}
+	stream.WriteObjectStart()
+	stream.WriteObjectField(encoder.union.discriminator)
+	stream.WriteString(tag)
+	if string(bytes.TrimLeft(encoded[1:], " \n")) == "}" {
+		stream.WriteObjectEnd()
+		return
+	}
+	stream.writeByte(',')
+	stream.buf = append(stream.buf, encoded[1:]...)
+	stream.indention -= stream.cfg.indentionStep
+}
+
+func (encoder *unionEncoder) discriminatorVisible(stream *Stream) bool {
+	if stream.fieldMask == nil {
+		return true
+	}
+	_, visible := stream.fieldMask.field(encoder.union.discriminator)
+	return visible
+}
+
+func (encoder *unionEncoder) IsEmpty(ptr unsafe.Pointer) bool {
+	return encoder.valType.UnsafeIndirect(ptr) == nil
+}
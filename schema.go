@@ -0,0 +1,453 @@
+package jsoniter
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modern-go/reflect2"
+)
+
+// JSONSchemaDialect is the JSON Schema version of the schemas generated by Schema
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema is a JSON Schema document or subschema, marshal it to get the JSON
+type JSONSchema map[string]interface{}
+
+// SchemaProvider describes the JSON of a codec or of a type with a JSON Schema.
+// A registered codec implementing it, or a type whose pointer implements it, contributes its own schema to Schema.
+// The method of a type is called on a zero value.
+type SchemaProvider interface {
+	JSONSchema() JSONSchema
+}
+
+var schemaProviderType = reflect2.TypeOfPtr((*SchemaProvider)(nil)).Elem()
+
+// Schema generates the JSON Schema (draft 2020-12) of typ as api encodes and decodes it.
+// Struct properties come from the same bindings as the codecs, after extensions and tags:
+// omitempty fields are not required, read-only and write-only fields are marked so,
+// string tagged fields are strings holding JSON, []byte is base64 and map keys are constrained by their type.
+// Named structs, slices, arrays and maps are put in $defs.
+// The codecs without a schema known, such as the ones supplied by extensions or marshalers, accept any JSON
+// unless they implement SchemaProvider.
+func Schema(api API, typ reflect.Type) (JSONSchema, error) {
+	cfg := api.(*frozenConfig)
+	generator := &schemaGenerator{
+		ctx: &ctx{
+			frozenConfig: cfg,
+			prefix:       "",
+			decoders:     map[reflect2.Type]ValDecoder{},
+			projected:    map[projectedType]ValDecoder{},
+			encoders:     map[reflect2.Type]ValEncoder{},
+			projection:   cfg.projection,
+		},
+		defs:      JSONSchema{},
+		names:     map[projectedType]string{},
+		inlinings: map[projectedType]string{},
+	}
+	valType := reflect2.Type2(typ)
+	schema := generator.schemaOf(valType, encoderOfType(generator.ctx, valType))
+	if generator.err != nil {
+		return nil, generator.err
+	}
+	root := JSONSchema{"$schema": JSONSchemaDialect}
+	for k, v := range schema {
+		root[k] = v
+	}
+	if len(generator.defs) > 0 {
+		root["$defs"] = generator.defs
+	}
+	return root, nil
+}
+
+type schemaGenerator struct {
+	ctx       *ctx
+	defs      JSONSchema
+	names     map[projectedType]string // names of the types in $defs, by type and projection
+	inlinings map[projectedType]string // projected types being inlined, with their name in $defs if they recur
+	err       error
+}
+
+func (generator *schemaGenerator) schemaOf(typ reflect2.Type, encoder ValEncoder) JSONSchema {
+	if provider, isProvider := encoder.(SchemaProvider); isProvider {
+		return provider.JSONSchema()
+	}
+	// the decorations of fields, and the codecs wrapping others
+	switch encoder := encoder.(type) {
+	case *placeholderEncoder:
+		return generator.schemaOf(typ, encoder.encoder)
+	case *lazyErrorEncoder:
+		if generator.err == nil {
+			generator.err = encoder.err
+		}
+		return JSONSchema{}
+	case *OptionalEncoder:
+		if typ.Kind() == reflect.Ptr {
+			return nullable(generator.schemaOf(typ.(*reflect2.UnsafePtrType).Elem(), encoder.ValueEncoder))
+		}
+	case *dereferenceEncoder:
+		return generator.schemaOf(typ.(*reflect2.UnsafePtrType).Elem(), encoder.ValueEncoder)
+	case *referenceEncoder:
+		return generator.schemaOf(typ, encoder.encoder)
+	case *stringModeNumberEncoder:
+		return jsonInString(generator.schemaOf(typ, encoder.elemEncoder))
+	case *stringModeStringEncoder:
+		return jsonInString(generator.schemaOf(typ, encoder.elemEncoder))
+	case *redactEncoder:
+		if generator.ctx.redaction == RedactNone {
+			return generator.schemaOf(typ, encoder.encoder)
+		}
+		return JSONSchema{"type": "string"}
+	case *timeFormatCodec:
+		if encoder.unit != 0 {
+			return JSONSchema{"type": "integer"}
+		}
+		if encoder.layout == time.RFC3339 || encoder.layout == time.RFC3339Nano {
+			return JSONSchema{"type": "string", "format": "date-time"}
+		}
+		return JSONSchema{"type": "string"}
+	case *bytesFormatCodec:
+		return nullable(JSONSchema{"type": "string", "contentEncoding": contentEncodingOf(encoder.encoding)})
+	case *durationUnitsCodec:
+		return JSONSchema{"type": "string"}
+	case *precisionFloatEncoder, *lossyFloat32Encoder, *lossyFloat64Encoder:
+		return JSONSchema{"type": "number"}
+	case *htmlEscapedStringEncoder:
+		return JSONSchema{"type": "string"}
+	}
+	if provider := typeSchemaProvider(typ); provider != nil {
+		return provider.JSONSchema()
+	}
+	if typ.Kind() == reflect.Ptr {
+		if provider := typeSchemaProvider(typ.(*reflect2.UnsafePtrType).Elem()); provider != nil {
+			return nullable(provider.JSONSchema())
+		}
+	}
+	switch encoder := encoder.(type) {
+	case *stringCodec:
+		return JSONSchema{"type": "string"}
+	case *boolCodec:
+		return JSONSchema{"type": "boolean"}
+	case *int8Codec:
+		return integerSchema(math.MinInt8, math.MaxInt8)
+	case *int16Codec:
+		return integerSchema(math.MinInt16, math.MaxInt16)
+	case *int32Codec:
+		return integerSchema(math.MinInt32, math.MaxInt32)
+	case *int64Codec:
+		return JSONSchema{"type": "integer"}
+	case *uint8Codec:
+		return integerSchema(0, math.MaxUint8)
+	case *uint16Codec:
+		return integerSchema(0, math.MaxUint16)
+	case *uint32Codec:
+		return integerSchema(0, math.MaxUint32)
+	case *uint64Codec:
+		return JSONSchema{"type": "integer", "minimum": 0}
+	case *float32Codec, *float64Codec, *jsonNumberCodec, *jsoniterNumberCodec:
+		return JSONSchema{"type": "number"}
+	case *base64Codec:
+		return nullable(JSONSchema{"type": "string", "contentEncoding": "base64"})
+	case *timeCodec:
+		return JSONSchema{"type": "string", "format": "date-time"}
+	case *textMarshalerEncoder, *directTextMarshalerEncoder:
+		return JSONSchema{"type": "string"}
+	case *optionalValueEncoder:
+		schema := generator.schemaOf(encoder.valueField.Type(), encoder.valueEncoder)
+		if typ.New().(optionalValue).nullIsPresent() {
+			return nullable(schema)
+		}
+		return schema
+	case *unionEncoder:
+		return generator.unionSchema(encoder)
+	case *structEncoder, *emptyStructEncoder:
+		return generator.defined(typ, func() JSONSchema {
+			return generator.structSchema(typ)
+		})
+	case *sliceEncoder:
+		return generator.defined(typ, func() JSONSchema {
+			elemType := typ.(*reflect2.UnsafeSliceType).Elem()
+			return nullable(JSONSchema{"type": "array", "items": generator.schemaOf(elemType, encoder.elemEncoder)})
+		})
+	case *arrayEncoder:
+		return generator.defined(typ, func() JSONSchema {
+			arrayType := typ.(*reflect2.UnsafeArrayType)
+			return JSONSchema{
+				"type":     "array",
+				"items":    generator.schemaOf(arrayType.Elem(), encoder.elemEncoder),
+				"minItems": arrayType.Len(),
+				"maxItems": arrayType.Len(),
+			}
+		})
+	case *mapEncoder:
+		return generator.defined(typ, func() JSONSchema {
+			return generator.mapSchema(encoder.mapType, encoder.keyEncoder, encoder.elemEncoder)
+		})
+	case *sortKeysMapEncoder:
+		return generator.defined(typ, func() JSONSchema {
+			return generator.mapSchema(encoder.mapType, encoder.keyEncoder, encoder.elemEncoder)
+		})
+	}
+	// interfaces, marshalers and the codecs supplied by extensions
+	return JSONSchema{}
+}
+
+// defined puts the schema of a named type in $defs, and refers to it.
+// Projected types are inlined, their schemas differ from one projection to another,
+// unless they refer to themselves within the same projection.
+func (generator *schemaGenerator) defined(typ reflect2.Type, schemaOf func() JSONSchema) JSONSchema {
+	if typ.Type1().Name() == "" {
+		return schemaOf()
+	}
+	key := projectedType{typ, generator.ctx.projection}
+	name, found := generator.names[key]
+	if found {
+		return schemaRef(name)
+	}
+	if key.projection != nil {
+		if name, inlining := generator.inlinings[key]; inlining {
+			if name == "" {
+				name = generator.reserveDef(typ)
+				generator.inlinings[key] = name
+			}
+			return schemaRef(name)
+		}
+		generator.inlinings[key] = ""
+		schema := schemaOf()
+		name = generator.inlinings[key]
+		delete(generator.inlinings, key)
+		if name == "" {
+			return schema
+		}
+		generator.names[key] = name
+		generator.defs[name] = schema
+		return schemaRef(name)
+	}
+	name = generator.reserveDef(typ)
+	generator.names[key] = name
+	generator.defs[name] = schemaOf()
+	return schemaRef(name)
+}
+
+// reserveDef reserves a name in $defs for typ, before generating its schema for the types referring to themselves
+func (generator *schemaGenerator) reserveDef(typ reflect2.Type) string {
+	name := typ.String()
+	for i := 2; generator.defs[name] != nil; i++ {
+		name = fmt.Sprintf("%s_%d", typ.String(), i)
+	}
+	generator.defs[name] = JSONSchema{}
+	return name
+}
+
+func schemaRef(name string) JSONSchema {
+	return JSONSchema{"$ref": "#/$defs/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(name)}
+}
+
+func (generator *schemaGenerator) structSchema(typ reflect2.Type) JSONSchema {
+	ctx := generator.ctx.append(typ.String())
+	structDescriptor := describeStruct(ctx, typ)
+	decoded := decodingBindings(ctx, structDescriptor)
+	properties := JSONSchema{}
+	required := []string{}
+	encodedBindings := map[*Binding]bool{}
+	for _, bindingTo := range encodingBindings(ctx, structDescriptor) {
+		if bindingTo.ignored {
+			continue
+		}
+		binding := bindingTo.binding
+		encodedBindings[binding] = true
+		encoder, omitEmpty, embeddedPtr := fieldEncoderOf(binding.Encoder)
+		schema := generator.fieldSchema(ctx.projection, binding, encoder)
+		if !decodesBinding(decoded, binding) {
+			schema = withKeyword(schema, "readOnly", true)
+		}
+		properties[bindingTo.toName] = schema
+		redactOmitted := isRedacted(binding.Encoder) && generator.ctx.redaction == RedactOmit
+		if !omitEmpty && !embeddedPtr && !redactOmitted {
+			required = append(required, bindingTo.toName)
+		}
+	}
+	// the names only decoded, write-only fields and aliases
+	fromNames := make([]string, 0, len(decoded))
+	for fromName := range decoded {
+		if properties[fromName] == nil {
+			fromNames = append(fromNames, fromName)
+		}
+	}
+	sort.Strings(fromNames)
+	for _, fromName := range fromNames {
+		binding := decoded[fromName]
+		encoder, _, _ := fieldEncoderOf(binding.Encoder)
+		schema := generator.fieldSchema(ctx.projection, binding, encoder)
+		if !encodedBindings[binding] {
+			schema = withKeyword(schema, "writeOnly", true)
+		}
+		properties[fromName] = schema
+	}
+	schema := JSONSchema{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	// the fields left out of the projection are unknown to the decoder, and skipped
+	if generator.ctx.disallowUnknownFields && ctx.projection == nil {
+		schema["additionalProperties"] = false
+	}
+	return schema
+}
+
+// fieldSchema generates the schema of the field of binding, decoded within projection if not nil
+func (generator *schemaGenerator) fieldSchema(projection *FieldMask, binding *Binding, encoder ValEncoder) JSONSchema {
+	if projection == nil {
+		return generator.schemaOf(binding.Field.Type(), encoder)
+	}
+	return generator.withProjection(projection.childOf(binding.FromNames), func() JSONSchema {
+		return generator.schemaOf(binding.Field.Type(), encoder)
+	})
+}
+
+// withProjection generates a schema with the projection of the value decoded, nil for the whole value
+func (generator *schemaGenerator) withProjection(projection *FieldMask, schemaOf func() JSONSchema) JSONSchema {
+	ctx := generator.ctx
+	defer func() {
+		generator.ctx = ctx
+	}()
+	generator.ctx = ctx.append("")
+	generator.ctx.projection = projection
+	return schemaOf()
+}
+
+// fieldEncoderOf unwraps the binding encoder down to the encoder of the field value
+func fieldEncoderOf(encoder ValEncoder) (fieldEncoder ValEncoder, omitEmpty bool, embeddedPtr bool) {
+	if structFieldEncoder, isFieldEncoder := encoder.(*structFieldEncoder); isFieldEncoder {
+		omitEmpty = structFieldEncoder.omitempty
+	}
+	for {
+		switch unwrapped := encoder.(type) {
+		case *structFieldEncoder:
+			encoder = unwrapped.fieldEncoder
+		case *dereferenceEncoder:
+			encoder, embeddedPtr = unwrapped.ValueEncoder, true
+		default:
+			return encoder, omitEmpty, embeddedPtr
+		}
+	}
+}
+
+func decodesBinding(decoded map[string]*Binding, binding *Binding) bool {
+	if _, isReadOnly := binding.Decoder.(*structFieldDecoder).fieldDecoder.(*readOnlyFieldDecoder); isReadOnly {
+		return false
+	}
+	for _, fromName := range binding.FromNames {
+		if decoded[fromName] == binding {
+			return true
+		}
+	}
+	return false
+}
+
+func (generator *schemaGenerator) mapSchema(mapType *reflect2.UnsafeMapType, keyEncoder, elemEncoder ValEncoder) JSONSchema {
+	// the projections of map values are told apart by key, the schema is the one of whole values
+	schema := JSONSchema{
+		"type": "object",
+		"additionalProperties": generator.withProjection(nil, func() JSONSchema {
+			return generator.schemaOf(mapType.Elem(), elemEncoder)
+		}),
+	}
+	if keySchema := mapKeySchema(mapType.Key(), keyEncoder); keySchema != nil {
+		schema["propertyNames"] = keySchema
+	}
+	return nullable(schema)
+}
+
+// mapKeySchema constrains the object keys written for keyType, nil if any string
+func mapKeySchema(keyType reflect2.Type, keyEncoder ValEncoder) JSONSchema {
+	if _, isNumeric := keyEncoder.(*numericMapKeyEncoder); !isNumeric {
+		return nil
+	}
+	switch keyType.Kind() {
+	case reflect.Bool:
+		return JSONSchema{"enum": []interface{}{"true", "false"}}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return JSONSchema{"pattern": "^-?[0-9]+$"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return JSONSchema{"pattern": "^[0-9]+$"}
+	case reflect.Float32, reflect.Float64:
+		return JSONSchema{"pattern": "^-?[0-9]+(\\.[0-9]+)?([eE][-+]?[0-9]+)?$"}
+	}
+	return nil
+}
+
+func (generator *schemaGenerator) unionSchema(encoder *unionEncoder) JSONSchema {
+	tags := make([]string, 0, len(encoder.union.variants))
+	for tag := range encoder.union.variants {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	variants := make([]interface{}, 0, len(tags))
+	for _, tag := range tags {
+		variantType := encoder.union.variants[tag]
+		variant := generator.schemaOf(variantType, encoderOfType(generator.ctx.append(tag), variantType))
+		discriminator := JSONSchema{"const": tag}
+		variants = append(variants, JSONSchema{
+			"allOf":      []interface{}{variant},
+			"properties": JSONSchema{encoder.union.discriminator: discriminator},
+			"required":   []string{encoder.union.discriminator},
+		})
+	}
+	return nullable(JSONSchema{"oneOf": variants})
+}
+
+func typeSchemaProvider(typ reflect2.Type) SchemaProvider {
+	if typ.Kind() == reflect.Interface || !reflect2.PtrTo(typ).Implements(schemaProviderType) {
+		return nil
+	}
+	return typ.New().(SchemaProvider)
+}
+
+func integerSchema(minimum, maximum int64) JSONSchema {
+	return JSONSchema{"type": "integer", "minimum": minimum, "maximum": maximum}
+}
+
+// jsonInString describes the strings holding the JSON of schema, written by the string tag option
+func jsonInString(schema JSONSchema) JSONSchema {
+	return JSONSchema{"type": "string", "contentMediaType": "application/json", "contentSchema": schema}
+}
+
+// nullable also accepts null
+func nullable(schema JSONSchema) JSONSchema {
+	switch typ := schema["type"].(type) {
+	case string:
+		return withKeyword(schema, "type", []string{typ, "null"})
+	case nil:
+		if len(schema) == 0 {
+			// already any
+			return schema
+		}
+	}
+	return JSONSchema{"anyOf": []interface{}{schema, JSONSchema{"type": "null"}}}
+}
+
+// withKeyword copies schema with keyword set, the schemas returned may be shared
+func withKeyword(schema JSONSchema, keyword string, value interface{}) JSONSchema {
+	copied := JSONSchema{keyword: value}
+	for k, v := range schema {
+		if k != keyword {
+			copied[k] = v
+		}
+	}
+	return copied
+}
+
+func contentEncodingOf(encoding bytesEncoding) string {
+	switch encoding {
+	case base64.StdEncoding, base64.RawStdEncoding:
+		return "base64"
+	case base64.URLEncoding, base64.RawURLEncoding:
+		return "base64url"
+	}
+	return "base16"
+}
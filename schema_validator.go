@@ -0,0 +1,885 @@
+package jsoniter
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// SchemaValidator checks JSON values against a JSON Schema (draft 2020-12), compiled once by CompileSchema.
+// The values are checked in a single walk of an Iterator, the applicators such as anyOf or $ref evaluated
+// from the keywords of their subschemas checked along the way. Only the values checked by enum or const
+// are read into an intermediate value.
+// Decoding does not validate: validate the documents with Validate, ValidateReader or ValidateNext beforehand.
+type SchemaValidator struct {
+	root *schemaNode
+}
+
+// schemaNode is a compiled schema or subschema
+type schemaNode struct {
+	never      bool          // the false schema
+	inPlace    []*schemaNode // the schema and the subschemas applying to the same value, checked in the same walk
+	types      uint8
+	enum       []interface{}
+	ref        *schemaNode
+	allOf      []*schemaNode
+	anyOf      []*schemaNode
+	oneOf      []*schemaNode
+	not        *schemaNode
+	ifSchema   *schemaNode
+	thenSchema *schemaNode
+	elseSchema *schemaNode
+
+	properties           map[string]*schemaNode
+	patternProperties    []*patternSchema
+	additionalProperties *schemaNode
+	propertyNames        *schemaNode
+	required             []string
+	minProperties        int
+	maxProperties        int
+
+	prefixItems []*schemaNode
+	items       *schemaNode
+	minItems    int
+	maxItems    int
+
+	minLength int
+	maxLength int
+	pattern   *regexp.Regexp
+
+	minimum          *float64
+	maximum          *float64
+	exclusiveMinimum *float64
+	exclusiveMaximum *float64
+	multipleOf       *float64
+}
+
+type patternSchema struct {
+	pattern *regexp.Regexp
+	schema  *schemaNode
+}
+
+const (
+	schemaNull uint8 = 1 << iota
+	schemaBoolean
+	schemaObject
+	schemaArray
+	schemaNumber
+	schemaInteger
+	schemaString
+	schemaAnyType uint8 = 0
+)
+
+var schemaTypeNames = []string{"null", "boolean", "object", "array", "number", "integer", "string"}
+
+// the keywords asserting on values this validator can not check, rejected rather than ignored
+var unsupportedSchemaKeywords = []string{"contains", "minContains", "maxContains", "dependentRequired",
+	"dependentSchemas", "unevaluatedProperties", "unevaluatedItems", "$dynamicRef", "$recursiveRef"}
+
+// CompileSchema compiles a JSON Schema document, for example the one generated by Schema and marshaled.
+// Only the $ref to the document itself are supported, and formats are annotations not checked.
+func CompileSchema(schema []byte) (*SchemaValidator, error) {
+	var document interface{}
+	if err := ConfigDefault.Unmarshal(schema, &document); err != nil {
+		return nil, err
+	}
+	compiler := &schemaCompiler{document: document, compiled: map[string]*schemaNode{}}
+	root, err := compiler.compileAt("")
+	if err != nil {
+		return nil, err
+	}
+	for _, node := range compiler.compiled {
+		node.inPlace = node.gatherInPlace(nil)
+	}
+	return &SchemaValidator{root: root}, nil
+}
+
+type schemaCompiler struct {
+	document interface{}
+	compiled map[string]*schemaNode // by JSON pointer in the document
+}
+
+func (compiler *schemaCompiler) compileAt(pointer string) (*schemaNode, error) {
+	if node := compiler.compiled[pointer]; node != nil {
+		return node, nil
+	}
+	value := compiler.document
+	if pointer != "" {
+		for _, token := range strings.Split(pointer[1:], "/") {
+			token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+			switch container := value.(type) {
+			case map[string]interface{}:
+				value = container[token]
+			case []interface{}:
+				index, err := strconv.Atoi(token)
+				if err != nil || index < 0 || index >= len(container) {
+					return nil, fmt.Errorf("schema #%s not found", pointer)
+				}
+				value = container[index]
+			default:
+				value = nil
+			}
+			if value == nil {
+				return nil, fmt.Errorf("schema #%s not found", pointer)
+			}
+		}
+	}
+	// registered before compiling, for the schemas referring to themselves
+	node := &schemaNode{}
+	compiler.compiled[pointer] = node
+	if err := compiler.compile(node, value, pointer); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+func (compiler *schemaCompiler) compile(node *schemaNode, value interface{}, pointer string) (err error) {
+	node.minProperties, node.maxProperties = -1, -1
+	node.minItems, node.maxItems = -1, -1
+	node.minLength, node.maxLength = -1, -1
+	switch schema := value.(type) {
+	case bool:
+		node.never = !schema
+		return nil
+	case map[string]interface{}:
+		return compiler.compileKeywords(node, schema, pointer)
+	}
+	return fmt.Errorf("schema #%s is not an object or a boolean", pointer)
+}
+
+func (compiler *schemaCompiler) compileKeywords(node *schemaNode, schema map[string]interface{}, pointer string) (err error) {
+	for _, keyword := range unsupportedSchemaKeywords {
+		if _, found := schema[keyword]; found {
+			return fmt.Errorf("schema #%s: %s is not supported", pointer, keyword)
+		}
+	}
+	if uniqueItems, _ := schema["uniqueItems"].(bool); uniqueItems {
+		return fmt.Errorf("schema #%s: uniqueItems is not supported", pointer)
+	}
+	subschema := func(keyword string) *schemaNode {
+		if err != nil || schema[keyword] == nil {
+			return nil
+		}
+		var sub *schemaNode
+		sub, err = compiler.compileAt(pointer + "/" + keyword)
+		return sub
+	}
+	subschemas := func(keyword string) []*schemaNode {
+		values, _ := schema[keyword].([]interface{})
+		if err != nil || values == nil {
+			return nil
+		}
+		subs := make([]*schemaNode, len(values))
+		for i := range values {
+			if subs[i], err = compiler.compileAt(fmt.Sprintf("%s/%s/%d", pointer, keyword, i)); err != nil {
+				return nil
+			}
+		}
+		return subs
+	}
+	count := func(keyword string) int {
+		if number, isNumber := schema[keyword].(float64); isNumber {
+			return int(number)
+		}
+		return -1
+	}
+	number := func(keyword string) *float64 {
+		if number, isNumber := schema[keyword].(float64); isNumber {
+			return &number
+		}
+		return nil
+	}
+	if ref, hasRef := schema["$ref"].(string); hasRef {
+		if !strings.HasPrefix(ref, "#") || (len(ref) > 1 && ref[1] != '/') {
+			return fmt.Errorf("schema #%s: only $ref to the document itself are supported, not %s", pointer, ref)
+		}
+		if node.ref, err = compiler.compileAt(ref[1:]); err != nil {
+			return err
+		}
+	}
+	switch typ := schema["type"].(type) {
+	case string:
+		node.types, err = schemaTypeOf(typ, pointer)
+	case []interface{}:
+		for _, elem := range typ {
+			name, _ := elem.(string)
+			var bit uint8
+			if bit, err = schemaTypeOf(name, pointer); err != nil {
+				return err
+			}
+			node.types |= bit
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if enum, hasEnum := schema["enum"].([]interface{}); hasEnum {
+		node.enum = enum
+	}
+	if constValue, hasConst := schema["const"]; hasConst {
+		node.enum = []interface{}{constValue}
+	}
+	node.allOf = subschemas("allOf")
+	node.anyOf = subschemas("anyOf")
+	node.oneOf = subschemas("oneOf")
+	node.not = subschema("not")
+	node.ifSchema = subschema("if")
+	node.thenSchema = subschema("then")
+	node.elseSchema = subschema("else")
+	if properties, hasProperties := schema["properties"].(map[string]interface{}); hasProperties {
+		node.properties = map[string]*schemaNode{}
+		for name := range properties {
+			escaped := strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+			if node.properties[name], err = compiler.compileAt(pointer + "/properties/" + escaped); err != nil {
+				return err
+			}
+		}
+	}
+	if patternProperties, hasPatterns := schema["patternProperties"].(map[string]interface{}); hasPatterns {
+		patterns := make([]string, 0, len(patternProperties))
+		for pattern := range patternProperties {
+			patterns = append(patterns, pattern)
+		}
+		sort.Strings(patterns)
+		for _, pattern := range patterns {
+			compiled, err := regexp.Compile(pattern)
+			if err != nil {
+				return fmt.Errorf("schema #%s: %s", pointer, err.Error())
+			}
+			escaped := strings.NewReplacer("~", "~0", "/", "~1").Replace(pattern)
+			sub, err := compiler.compileAt(pointer + "/patternProperties/" + escaped)
+			if err != nil {
+				return err
+			}
+			node.patternProperties = append(node.patternProperties, &patternSchema{compiled, sub})
+		}
+	}
+	node.additionalProperties = subschema("additionalProperties")
+	node.propertyNames = subschema("propertyNames")
+	if required, hasRequired := schema["required"].([]interface{}); hasRequired {
+		for _, name := range required {
+			if name, isString := name.(string); isString {
+				node.required = append(node.required, name)
+			}
+		}
+	}
+	node.minProperties = count("minProperties")
+	node.maxProperties = count("maxProperties")
+	node.prefixItems = subschemas("prefixItems")
+	node.items = subschema("items")
+	node.minItems = count("minItems")
+	node.maxItems = count("maxItems")
+	node.minLength = count("minLength")
+	node.maxLength = count("maxLength")
+	if pattern, hasPattern := schema["pattern"].(string); hasPattern {
+		if node.pattern, err = regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("schema #%s: %s", pointer, err.Error())
+		}
+	}
+	node.minimum = number("minimum")
+	node.maximum = number("maximum")
+	node.exclusiveMinimum = number("exclusiveMinimum")
+	node.exclusiveMaximum = number("exclusiveMaximum")
+	node.multipleOf = number("multipleOf")
+	return err
+}
+
+// gatherInPlace gathers node and the subschemas applying to the same value, each once
+func (node *schemaNode) gatherInPlace(nodes []*schemaNode) []*schemaNode {
+	for _, gathered := range nodes {
+		if gathered == node {
+			return nodes
+		}
+	}
+	nodes = append(nodes, node)
+	for _, sub := range node.applicators() {
+		nodes = sub.gatherInPlace(nodes)
+	}
+	return nodes
+}
+
+func (node *schemaNode) applicators() []*schemaNode {
+	var subs []*schemaNode
+	for _, sub := range []*schemaNode{node.ref, node.not, node.ifSchema, node.thenSchema, node.elseSchema} {
+		if sub != nil {
+			subs = append(subs, sub)
+		}
+	}
+	subs = append(subs, node.allOf...)
+	subs = append(subs, node.anyOf...)
+	return append(subs, node.oneOf...)
+}
+
+func schemaTypeOf(name string, pointer string) (uint8, error) {
+	for i, typeName := range schemaTypeNames {
+		if typeName == name {
+			return 1 << uint(i), nil
+		}
+	}
+	return 0, fmt.Errorf("schema #%s: unknown type %s", pointer, name)
+}
+
+// Validate checks data is a single JSON value valid against the schema
+func (validator *SchemaValidator) Validate(data []byte) error {
+	iter := ConfigDefault.BorrowIterator(data)
+	defer ConfigDefault.ReturnIterator(iter)
+	return validator.validateAll(iter)
+}
+
+// ValidateReader checks reader holds a single JSON value valid against the schema
+func (validator *SchemaValidator) ValidateReader(reader io.Reader) error {
+	return validator.validateAll(Parse(ConfigDefault, reader, 512))
+}
+
+func (validator *SchemaValidator) validateAll(iter *Iterator) error {
+	validator.ValidateNext(iter)
+	c := iter.nextToken()
+	if c == 0 {
+		if iter.Error == io.EOF {
+			return nil
+		}
+		return iter.Error
+	}
+	iter.ReportError("Validate", "there are bytes left after validate")
+	return iter.Error
+}
+
+// ValidateNext walks the next value of iter, a value not valid against the schema is reported as iter.Error
+func (validator *SchemaValidator) ValidateNext(iter *Iterator) {
+	walked := walkSchemaValue(iter, validator.root.inPlace, nil, false)
+	if iter.Error != nil && iter.Error != io.EOF {
+		return
+	}
+	if failure := walked.result(validator.root, nil); failure != nil {
+		iter.ReportError("validate", failure.String())
+	}
+}
+
+// instancePath locates the value validated, rendered as a JSON pointer. The root is nil.
+type instancePath struct {
+	parent *instancePath
+	token  string
+}
+
+func (path *instancePath) child(token string) *instancePath {
+	return &instancePath{parent: path, token: token}
+}
+
+func (path *instancePath) String() string {
+	var tokens []string
+	for ; path != nil; path = path.parent {
+		tokens = append(tokens, strings.NewReplacer("~", "~0", "/", "~1").Replace(path.token))
+	}
+	var builder strings.Builder
+	builder.WriteByte('#')
+	for i := len(tokens) - 1; i >= 0; i-- {
+		builder.WriteByte('/')
+		builder.WriteString(tokens[i])
+	}
+	return builder.String()
+}
+
+// schemaFailure tells why a value is not valid, rendered only when reported
+type schemaFailure struct {
+	path     *instancePath
+	message  string
+	branches []*schemaFailure // the failures of the anyOf schemas
+}
+
+// the failures of anyOf schemas are rendered up to this length
+const maxSchemaFailureLength = 512
+
+func failAt(path *instancePath, format string, args ...interface{}) *schemaFailure {
+	return &schemaFailure{path: path, message: fmt.Sprintf(format, args...)}
+}
+
+func (failure *schemaFailure) String() string {
+	var builder strings.Builder
+	failure.render(&builder)
+	return builder.String()
+}
+
+func (failure *schemaFailure) render(builder *strings.Builder) {
+	builder.WriteString(failure.path.String())
+	builder.WriteString(": ")
+	builder.WriteString(failure.message)
+	for i, branch := range failure.branches {
+		if builder.Len() > maxSchemaFailureLength {
+			builder.WriteString("; ...")
+			return
+		}
+		if i == 0 {
+			builder.WriteString(": ")
+		} else {
+			builder.WriteString("; ")
+		}
+		branch.render(builder)
+	}
+}
+
+// schemaValue is a value walked once for all the schemas applying to it
+type schemaValue struct {
+	path     *instancePath
+	nodes    []*schemaNode    // the schemas evaluated, with their subschemas applying in place
+	failures []*schemaFailure // the first failure of the keywords of each node, other than the in place applicators
+	value    interface{}      // the value read, only when an enum or a const needs it
+}
+
+// inPlaceOf gathers the schemas and their subschemas applying in place, without duplicates
+func inPlaceOf(schemas []*schemaNode) []*schemaNode {
+	if len(schemas) == 1 {
+		return schemas[0].inPlace
+	}
+	var nodes []*schemaNode
+	for _, schema := range schemas {
+		for _, node := range schema.inPlace {
+			nodes = appendSchemaNode(nodes, node)
+		}
+	}
+	return nodes
+}
+
+func appendSchemaNode(nodes []*schemaNode, node *schemaNode) []*schemaNode {
+	for _, appended := range nodes {
+		if appended == node {
+			return nodes
+		}
+	}
+	return append(nodes, node)
+}
+
+// walkSchemaValue walks the next value of iter once, checking the keywords of nodes on it and on the values it holds.
+// needValue asks for the value read, to check an enum or a const.
+func walkSchemaValue(iter *Iterator, nodes []*schemaNode, path *instancePath, needValue bool) *schemaValue {
+	walked := &schemaValue{path: path, nodes: nodes, failures: make([]*schemaFailure, len(nodes))}
+	for i, node := range nodes {
+		if node.never {
+			walked.failures[i] = failAt(path, "no value is allowed")
+		}
+		if node.enum != nil {
+			needValue = true
+		}
+	}
+	switch iter.WhatIsNext() {
+	case NilValue:
+		iter.Skip()
+		walked.checkType(schemaNull, "null")
+	case BoolValue:
+		value := iter.ReadBool()
+		if needValue {
+			walked.value = value
+		}
+		walked.checkType(schemaBoolean, "boolean")
+	case StringValue:
+		value := iter.ReadString()
+		if needValue {
+			walked.value = value
+		}
+		walked.check(func(node *schemaNode) *schemaFailure {
+			return node.checkString(value, path)
+		})
+	case NumberValue:
+		literal := iter.ReadNumber().String()
+		value, err := strconv.ParseFloat(literal, 64)
+		if needValue {
+			walked.value = value
+		}
+		walked.check(func(node *schemaNode) *schemaFailure {
+			if err != nil {
+				return failAt(path, "invalid number %s", literal)
+			}
+			return node.checkNumber(literal, value, path)
+		})
+	case ArrayValue:
+		walked.checkType(schemaArray, "array")
+		walked.walkArray(iter, needValue)
+	case ObjectValue:
+		walked.checkType(schemaObject, "object")
+		walked.walkObject(iter, needValue)
+	default:
+		iter.Skip()
+	}
+	if needValue {
+		walked.check(func(node *schemaNode) *schemaFailure {
+			return node.checkEnum(walked.value, path)
+		})
+	}
+	return walked
+}
+
+// check records the failure of each node not failed yet
+func (walked *schemaValue) check(check func(node *schemaNode) *schemaFailure) {
+	for i, node := range walked.nodes {
+		if walked.failures[i] == nil {
+			walked.failures[i] = check(node)
+		}
+	}
+}
+
+func (walked *schemaValue) checkType(typ uint8, found string) {
+	walked.check(func(node *schemaNode) *schemaFailure {
+		if !node.allows(typ) {
+			return node.typeFailure(walked.path, found)
+		}
+		return nil
+	})
+}
+
+func (walked *schemaValue) walkArray(iter *Iterator, needValue bool) {
+	var values []interface{}
+	if needValue {
+		values = []interface{}{}
+	}
+	count := 0
+	iter.ReadArrayCB(func(iter *Iterator) bool {
+		var itemSchemas []*schemaNode
+		for i, node := range walked.nodes {
+			if item := node.itemSchema(count); item != nil && walked.failures[i] == nil {
+				itemSchemas = appendSchemaNode(itemSchemas, item)
+			}
+		}
+		var value interface{}
+		if itemSchemas == nil {
+			value = skipSchemaValue(iter, needValue)
+		} else {
+			item := walkSchemaValue(iter, inPlaceOf(itemSchemas), walked.path.child(strconv.Itoa(count)), needValue)
+			walked.check(func(node *schemaNode) *schemaFailure {
+				if itemSchema := node.itemSchema(count); itemSchema != nil {
+					return item.result(itemSchema, nil)
+				}
+				return nil
+			})
+			value = item.value
+		}
+		if needValue {
+			values = append(values, value)
+		}
+		count++
+		return true
+	})
+	walked.value = values
+	walked.check(func(node *schemaNode) *schemaFailure {
+		if node.minItems >= 0 && count < node.minItems {
+			return failAt(walked.path, "expect at least %d items, but found %d", node.minItems, count)
+		}
+		if node.maxItems >= 0 && count > node.maxItems {
+			return failAt(walked.path, "expect at most %d items, but found %d", node.maxItems, count)
+		}
+		return nil
+	})
+}
+
+func (walked *schemaValue) walkObject(iter *Iterator, needValue bool) {
+	var values map[string]interface{}
+	if needValue {
+		values = map[string]interface{}{}
+	}
+	var fields map[string]bool
+	for _, node := range walked.nodes {
+		if len(node.required) > 0 {
+			fields = map[string]bool{}
+		}
+	}
+	count := 0
+	iter.ReadObjectCB(func(iter *Iterator, field string) bool {
+		count++
+		if fields != nil {
+			fields[field] = true
+		}
+		fieldPath := walked.path.child(field)
+		var fieldSchemas []*schemaNode
+		walked.check(func(node *schemaNode) *schemaFailure {
+			if node.propertyNames != nil {
+				if failure := node.propertyNames.checkName(iter.cfg, field, fieldPath); failure != nil {
+					return failure
+				}
+			}
+			for _, schema := range node.propertySchemas(field) {
+				fieldSchemas = appendSchemaNode(fieldSchemas, schema)
+			}
+			return nil
+		})
+		var value interface{}
+		if fieldSchemas == nil {
+			value = skipSchemaValue(iter, needValue)
+		} else {
+			property := walkSchemaValue(iter, inPlaceOf(fieldSchemas), fieldPath, needValue)
+			walked.check(func(node *schemaNode) *schemaFailure {
+				for _, schema := range node.propertySchemas(field) {
+					if failure := property.result(schema, nil); failure != nil {
+						return failure
+					}
+				}
+				return nil
+			})
+			value = property.value
+		}
+		if needValue {
+			values[field] = value
+		}
+		return true
+	})
+	walked.value = values
+	walked.check(func(node *schemaNode) *schemaFailure {
+		for _, name := range node.required {
+			if !fields[name] {
+				return failAt(walked.path, "missing required property %q", name)
+			}
+		}
+		if node.minProperties >= 0 && count < node.minProperties {
+			return failAt(walked.path, "expect at least %d properties, but found %d", node.minProperties, count)
+		}
+		if node.maxProperties >= 0 && count > node.maxProperties {
+			return failAt(walked.path, "expect at most %d properties, but found %d", node.maxProperties, count)
+		}
+		return nil
+	})
+}
+
+// skipSchemaValue skips the next value of iter, or reads it if needed
+func skipSchemaValue(iter *Iterator, needValue bool) interface{} {
+	if needValue {
+		return readSchemaInstance(iter)
+	}
+	iter.Skip()
+	return nil
+}
+
+// result tells why the value is not valid against node, one of the nodes walked, nil if valid.
+// The in place applicators of node are evaluated from the failures of the keywords of their subschemas.
+func (walked *schemaValue) result(node *schemaNode, evaluating []*schemaNode) *schemaFailure {
+	index := -1
+	for i, walkedNode := range walked.nodes {
+		if walkedNode == node {
+			index = i
+		}
+	}
+	if walked.failures[index] != nil {
+		return walked.failures[index]
+	}
+	for _, evaluated := range evaluating {
+		if evaluated == node {
+			// applying to itself in place, nothing more to check
+			return nil
+		}
+	}
+	evaluating = append(evaluating[:len(evaluating):len(evaluating)], node)
+	if node.ref != nil {
+		if failure := walked.result(node.ref, evaluating); failure != nil {
+			return failure
+		}
+	}
+	for _, sub := range node.allOf {
+		if failure := walked.result(sub, evaluating); failure != nil {
+			return failure
+		}
+	}
+	if node.anyOf != nil {
+		var branches []*schemaFailure
+		for _, sub := range node.anyOf {
+			failure := walked.result(sub, evaluating)
+			if failure == nil {
+				branches = nil
+				break
+			}
+			branches = append(branches, failure)
+		}
+		if branches != nil {
+			return &schemaFailure{path: walked.path, message: "not valid against any schema of anyOf", branches: branches}
+		}
+	}
+	if node.oneOf != nil {
+		valid := 0
+		for _, sub := range node.oneOf {
+			if walked.result(sub, evaluating) == nil {
+				valid++
+			}
+		}
+		if valid != 1 {
+			return failAt(walked.path, "valid against %d schemas of oneOf, expect exactly 1", valid)
+		}
+	}
+	if node.not != nil && walked.result(node.not, evaluating) == nil {
+		return failAt(walked.path, "valid against the schema of not")
+	}
+	if node.ifSchema != nil {
+		if walked.result(node.ifSchema, evaluating) == nil {
+			if node.thenSchema != nil {
+				return walked.result(node.thenSchema, evaluating)
+			}
+		} else if node.elseSchema != nil {
+			return walked.result(node.elseSchema, evaluating)
+		}
+	}
+	return nil
+}
+
+// readSchemaInstance reads the next value of iter as the schema values are read, whatever the config of iter
+func readSchemaInstance(iter *Iterator) interface{} {
+	switch iter.WhatIsNext() {
+	case StringValue:
+		return iter.ReadString()
+	case NumberValue:
+		return iter.ReadFloat64()
+	case BoolValue:
+		return iter.ReadBool()
+	case ArrayValue:
+		arr := []interface{}{}
+		iter.ReadArrayCB(func(iter *Iterator) bool {
+			arr = append(arr, readSchemaInstance(iter))
+			return true
+		})
+		return arr
+	case ObjectValue:
+		obj := map[string]interface{}{}
+		iter.ReadObjectCB(func(iter *Iterator, field string) bool {
+			obj[field] = readSchemaInstance(iter)
+			return true
+		})
+		return obj
+	}
+	iter.Skip()
+	return nil
+}
+
+func (node *schemaNode) allows(typ uint8) bool {
+	return node.types == schemaAnyType || node.types&typ != 0
+}
+
+func (node *schemaNode) typeFailure(path *instancePath, found string) *schemaFailure {
+	expected := []string{}
+	for i, typeName := range schemaTypeNames {
+		if node.types&(1<<uint(i)) != 0 {
+			expected = append(expected, typeName)
+		}
+	}
+	return failAt(path, "expect %s, but found %s", strings.Join(expected, " or "), found)
+}
+
+func (node *schemaNode) checkString(value string, path *instancePath) *schemaFailure {
+	if !node.allows(schemaString) {
+		return node.typeFailure(path, "string")
+	}
+	if node.minLength >= 0 || node.maxLength >= 0 {
+		length := utf8.RuneCountInString(value)
+		if node.minLength >= 0 && length < node.minLength {
+			return failAt(path, "expect at least %d characters, but found %d", node.minLength, length)
+		}
+		if node.maxLength >= 0 && length > node.maxLength {
+			return failAt(path, "expect at most %d characters, but found %d", node.maxLength, length)
+		}
+	}
+	if node.pattern != nil && !node.pattern.MatchString(value) {
+		return failAt(path, "%s does not match %s", shortSchemaValue(value), node.pattern.String())
+	}
+	return nil
+}
+
+func (node *schemaNode) checkNumber(literal string, value float64, path *instancePath) *schemaFailure {
+	isInteger := !math.IsInf(value, 0) && value == math.Trunc(value)
+	if !node.allows(schemaNumber) && !(isInteger && node.allows(schemaInteger)) {
+		if node.allows(schemaInteger) {
+			return node.typeFailure(path, literal)
+		}
+		return node.typeFailure(path, "number")
+	}
+	if node.minimum != nil && value < *node.minimum {
+		return failAt(path, "%s is less than minimum %v", literal, *node.minimum)
+	}
+	if node.maximum != nil && value > *node.maximum {
+		return failAt(path, "%s is greater than maximum %v", literal, *node.maximum)
+	}
+	if node.exclusiveMinimum != nil && value <= *node.exclusiveMinimum {
+		return failAt(path, "%s is not greater than exclusiveMinimum %v", literal, *node.exclusiveMinimum)
+	}
+	if node.exclusiveMaximum != nil && value >= *node.exclusiveMaximum {
+		return failAt(path, "%s is not less than exclusiveMaximum %v", literal, *node.exclusiveMaximum)
+	}
+	if node.multipleOf != nil {
+		quotient := value / *node.multipleOf
+		if quotient != math.Trunc(quotient) {
+			return failAt(path, "%s is not a multiple of %v", literal, *node.multipleOf)
+		}
+	}
+	return nil
+}
+
+func (node *schemaNode) checkEnum(value interface{}, path *instancePath) *schemaFailure {
+	if node.enum == nil {
+		return nil
+	}
+	for _, allowed := range node.enum {
+		if reflect.DeepEqual(value, allowed) {
+			return nil
+		}
+	}
+	return failAt(path, "%s is not one of the values allowed", shortSchemaValue(value))
+}
+
+// checkName validates the name of a property against the schema of propertyNames
+func (node *schemaNode) checkName(cfg *frozenConfig, name string, path *instancePath) *schemaFailure {
+	stream := cfg.BorrowStream(nil)
+	defer cfg.ReturnStream(stream)
+	stream.WriteString(name)
+	iter := cfg.BorrowIterator(stream.Buffer())
+	defer cfg.ReturnIterator(iter)
+	return walkSchemaValue(iter, node.inPlace, path, false).result(node, nil)
+}
+
+// the strings in failures are truncated to this number of bytes
+const maxSchemaValueLength = 40
+
+// shortSchemaValue describes a value in a failure, without its content if it is an array or an object
+func shortSchemaValue(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		if len(value) > maxSchemaValueLength {
+			end := maxSchemaValueLength
+			for end > 0 && !utf8.RuneStart(value[end]) {
+				end--
+			}
+			return strconv.Quote(value[:end]) + "..."
+		}
+		return strconv.Quote(value)
+	case float64:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	case bool:
+		return strconv.FormatBool(value)
+	case []interface{}:
+		return "array"
+	}
+	return "object"
+}
+
+// itemSchema finds the schema the item at index is validated against, nil if none
+func (node *schemaNode) itemSchema(index int) *schemaNode {
+	if index < len(node.prefixItems) {
+		return node.prefixItems[index]
+	}
+	return node.items
+}
+
+// propertySchemas finds the schemas the value of field is validated against
+func (node *schemaNode) propertySchemas(field string) []*schemaNode {
+	var schemas []*schemaNode
+	if property := node.properties[field]; property != nil {
+		schemas = append(schemas, property)
+	}
+	for _, patternProperty := range node.patternProperties {
+		if patternProperty.pattern.MatchString(field) {
+			schemas = append(schemas, patternProperty.schema)
+		}
+	}
+	if schemas == nil && node.additionalProperties != nil {
+		schemas = append(schemas, node.additionalProperties)
+	}
+	return schemas
+}
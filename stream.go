@@ -12,6 +12,8 @@ type Stream struct {
 	buf        []byte
 	Error      error
 	indention  int
+	fieldMask  *FieldMask
+	redaction  RedactionPolicy
 	Attachment interface{} // open for customized encoder
 }
 
@@ -20,12 +22,14 @@ type Stream struct {
 // out can be nil if write to internal buffer.
 // bufSize is the initial size for the internal buffer in bytes.
 func NewStream(cfg API, out io.Writer, bufSize int) *Stream {
+	frozenConfig := cfg.(*frozenConfig)
 	return &Stream{
-		cfg:       cfg.(*frozenConfig),
+		cfg:       frozenConfig,
 		out:       out,
 		buf:       make([]byte, 0, bufSize),
 		Error:     nil,
 		indention: 0,
+		redaction: frozenConfig.redaction,
 	}
 }
 
@@ -34,6 +38,17 @@ func (stream *Stream) Pool() StreamPool {
 	return stream.cfg
 }
 
+// borrowSubStream borrows a stream writing to its own buffer with the per-stream state of stream:
+// attachment, indention, field mask and redaction policy
+func (stream *Stream) borrowSubStream() *Stream {
+	subStream := stream.cfg.BorrowStream(nil)
+	subStream.Attachment = stream.Attachment
+	subStream.indention = stream.indention
+	subStream.fieldMask = stream.fieldMask
+	subStream.redaction = stream.redaction
+	return subStream
+}
+
 // Reset reuse this stream instance by assign a new writer
 func (stream *Stream) Reset(out io.Writer) {
 	stream.out = out
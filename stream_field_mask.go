@@ -0,0 +1,86 @@
+package jsoniter
+
+import (
+	"strings"
+)
+
+// FieldMask selects the object fields written by a stream, by dot separated JSON paths like "owner.email".
+// Paths go through arrays, "owners.email" applies to every element of owners.
+// The mask applies to struct and map encoders, values written by a Marshaler are not masked.
+type FieldMask struct {
+	exclude  bool
+	children map[string]*FieldMask
+}
+
+// IncludeFields creates a FieldMask writing only the given paths.
+// A path selects its whole value, its parents are written with only the selected fields.
+func IncludeFields(paths ...string) *FieldMask {
+	return newFieldMask(false, paths)
+}
+
+// ExcludeFields creates a FieldMask writing everything but the given paths.
+func ExcludeFields(paths ...string) *FieldMask {
+	return newFieldMask(true, paths)
+}
+
+func newFieldMask(exclude bool, paths []string) *FieldMask {
+	mask := &FieldMask{exclude: exclude, children: map[string]*FieldMask{}}
+	for _, path := range paths {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		node := mask
+		for _, name := range strings.Split(path, ".") {
+			if node.children == nil {
+				// the parent path is already selected as a whole
+				break
+			}
+			child := node.children[name]
+			if child == nil {
+				child = &FieldMask{exclude: exclude, children: map[string]*FieldMask{}}
+				node.children[name] = child
+			}
+			node = child
+		}
+		node.children = nil
+	}
+	return mask
+}
+
+// field tells if the named field is written, and the mask of its value, nil for no mask
+func (mask *FieldMask) field(name string) (*FieldMask, bool) {
+	child, found := mask.children[name]
+	if !found {
+		return nil, mask.exclude
+	}
+	if child.children == nil {
+		return nil, !mask.exclude
+	}
+	return child, true
+}
+
+// wants tells if one of the names is written
+func (mask *FieldMask) wants(names []string) bool {
+	for _, name := range names {
+		if _, visible := mask.field(name); visible {
+			return true
+		}
+	}
+	return false
+}
+
+// childOf returns the mask of the value selected by one of the names, nil for no mask
+func (mask *FieldMask) childOf(names []string) *FieldMask {
+	for _, name := range names {
+		if child, visible := mask.field(name); visible && child != nil {
+			return child
+		}
+	}
+	return nil
+}
+
+// SetFieldMask applies mask to the following writes, nil to write every field.
+func (stream *Stream) SetFieldMask(mask *FieldMask) {
+	stream.fieldMask = mask
+}
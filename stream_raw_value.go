@@ -0,0 +1,116 @@
+package jsoniter
+
+import (
+	"io"
+)
+
+// validValue reports why data is not exactly one JSON value, nil if it is
+func (cfg *frozenConfig) validValue(data []byte) error {
+	iter := cfg.BorrowIterator(data)
+	defer cfg.ReturnIterator(iter)
+	iter.Skip()
+	if iter.Error == nil && iter.nextToken() != 0 {
+		iter.ReportError("validValue", "unexpected data after top-level value")
+	}
+	if iter.Error == io.EOF {
+		return nil
+	}
+	return iter.Error
+}
+
+// writeRawValue writes the JSON value without its insignificant spaces,
+// indented to the current level if the config has an indention step.
+// Unbalanced data, only written when not validated, is written as is.
+func (stream *Stream) writeRawValue(data []byte) {
+	if !isRawBalanced(data) {
+		stream.buf = append(stream.buf, data...)
+		return
+	}
+	indention := stream.indention
+	inString := false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		if inString {
+			stream.buf = append(stream.buf, c)
+			switch c {
+			case '\\':
+				if i+1 < len(data) {
+					i++
+					stream.buf = append(stream.buf, data[i])
+				}
+			case '"':
+				inString = false
+			}
+			continue
+		}
+		switch c {
+		case ' ', '\t', '\n', '\r':
+		case '"':
+			inString = true
+			stream.buf = append(stream.buf, c)
+		case '{', '[':
+			next := i + 1
+			for next < len(data) && isRawSpace(data[next]) {
+				next++
+			}
+			if next < len(data) && (data[next] == '}' || data[next] == ']') {
+				stream.buf = append(stream.buf, c, data[next])
+				i = next
+				continue
+			}
+			stream.indention += stream.cfg.indentionStep
+			stream.writeByte(c)
+			stream.writeIndention(0)
+		case '}', ']':
+			stream.writeIndention(stream.cfg.indentionStep)
+			stream.indention -= stream.cfg.indentionStep
+			stream.writeByte(c)
+		case ',':
+			stream.WriteMore()
+		case ':':
+			if stream.indention > 0 {
+				stream.writeTwoBytes(':', ' ')
+			} else {
+				stream.writeByte(':')
+			}
+		default:
+			stream.buf = append(stream.buf, c)
+		}
+	}
+	stream.indention = indention
+}
+
+// isRawBalanced tells if the objects and arrays of data are closed in order, and its strings terminated
+func isRawBalanced(data []byte) bool {
+	var opened []byte
+	inString := false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		if inString {
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			inString = true
+		case '{':
+			opened = append(opened, '}')
+		case '[':
+			opened = append(opened, ']')
+		case '}', ']':
+			if len(opened) == 0 || opened[len(opened)-1] != c {
+				return false
+			}
+			opened = opened[:len(opened)-1]
+		}
+	}
+	return !inString && len(opened) == 0
+}
+
+func isRawSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
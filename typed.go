@@ -0,0 +1,151 @@
+package jsoniter
+
+import (
+	"io"
+	"unsafe"
+
+	"github.com/modern-go/reflect2"
+)
+
+// UnmarshalAs reads data as a T, same as Unmarshal into a new T
+func UnmarshalAs[T any](data []byte) (T, error) {
+	var val T
+	err := DecoderFor[T](ConfigDefault).Unmarshal(data, &val)
+	return val, err
+}
+
+// MarshalT writes val as JSON, same as Marshal without boxing val into an interface{}
+func MarshalT[T any](val T) ([]byte, error) {
+	return EncoderFor[T](ConfigDefault).Marshal(&val)
+}
+
+// TypedDecoder is the decoder of T resolved once for an API.
+// It reads into *T without the interface{} boxing and the cache lookup of ReadVal.
+type TypedDecoder[T any] struct {
+	cfg     *frozenConfig
+	decoder ValDecoder
+}
+
+// DecoderFor resolves the decoder of T for api
+func DecoderFor[T any](api API) *TypedDecoder[T] {
+	cfg := api.(*frozenConfig)
+	return &TypedDecoder[T]{cfg: cfg, decoder: cfg.DecoderOf(reflect2.TypeOfPtr((*T)(nil)))}
+}
+
+// Decode reads the next value of iter into val, same as iter.ReadVal(val)
+func (typed *TypedDecoder[T]) Decode(iter *Iterator, val *T) {
+	if iter.cfg != typed.cfg {
+		// the codecs differ from one config to another
+		iter.ReadVal(val)
+		return
+	}
+	if val == nil {
+		iter.ReportError("ReadVal", "can not read into nil pointer")
+		return
+	}
+	depth := iter.depth
+	typed.decoder.Decode(unsafe.Pointer(val), iter)
+	if iter.depth != depth {
+		iter.ReportError("ReadVal", "unexpected mismatched nesting")
+	}
+}
+
+// Unmarshal reads data into val, same as the Unmarshal of the API
+func (typed *TypedDecoder[T]) Unmarshal(data []byte, val *T) error {
+	iter := typed.cfg.BorrowIterator(data)
+	defer typed.cfg.ReturnIterator(iter)
+	typed.Decode(iter, val)
+	c := iter.nextToken()
+	if c == 0 {
+		if iter.Error == io.EOF {
+			return nil
+		}
+		return iter.Error
+	}
+	iter.ReportError("Unmarshal", "there are bytes left after unmarshal")
+	return iter.Error
+}
+
+// DecodeFrom reads the next value of decoder into val, same as decoder.Decode(val)
+func (typed *TypedDecoder[T]) DecodeFrom(decoder *Decoder, val *T) error {
+	iter := decoder.iter
+	if iter.head == iter.tail && iter.reader != nil {
+		if !iter.loadMore() {
+			return io.EOF
+		}
+	}
+	typed.Decode(iter, val)
+	if iter.Error == io.EOF {
+		return nil
+	}
+	return iter.Error
+}
+
+// TypedEncoder is the encoder of T resolved once for an API.
+// It writes *T without the interface{} boxing and the cache lookup of WriteVal.
+type TypedEncoder[T any] struct {
+	cfg          *frozenConfig
+	encoder      ValEncoder
+	valueEncoder ValEncoder // writes the non nil *T, nil if encoder must be used
+}
+
+// EncoderFor resolves the encoder of T for api
+func EncoderFor[T any](api API) *TypedEncoder[T] {
+	cfg := api.(*frozenConfig)
+	// the encoder of *T, as WriteVal(val) with val a *T uses
+	ptrType := reflect2.TypeOfPtr((*T)(nil))
+	encoder := cfg.EncoderOf(ptrType)
+	return &TypedEncoder[T]{cfg: cfg, encoder: encoder, valueEncoder: valueEncoderOf(ptrType, encoder)}
+}
+
+// valueEncoderOf unwraps the encoder of a pointer type, to write the value without taking the address of the pointer
+func valueEncoderOf(ptrType reflect2.PtrType, encoder ValEncoder) ValEncoder {
+	onePtr, isOnePtr := encoder.(*onePtrEncoder)
+	if !isOnePtr {
+		return nil
+	}
+	switch encoder := onePtr.encoder.(type) {
+	case *OptionalEncoder:
+		return encoder.ValueEncoder
+	case *streamMarshalerEncoder:
+		return &streamMarshalerEncoder{valType: ptrType.Elem(), byPointer: true}
+	}
+	return nil
+}
+
+// Encode writes val into stream, same as stream.WriteVal(val)
+func (typed *TypedEncoder[T]) Encode(stream *Stream, val *T) {
+	if stream.cfg != typed.cfg {
+		stream.WriteVal(val)
+		return
+	}
+	if typed.valueEncoder == nil {
+		typed.encoder.Encode(unsafe.Pointer(val), stream)
+	} else if val == nil {
+		stream.WriteNil()
+	} else {
+		typed.valueEncoder.Encode(unsafe.Pointer(val), stream)
+	}
+}
+
+// Marshal writes val as JSON, same as the Marshal of the API
+func (typed *TypedEncoder[T]) Marshal(val *T) ([]byte, error) {
+	stream := typed.cfg.BorrowStream(nil)
+	defer typed.cfg.ReturnStream(stream)
+	typed.Encode(stream, val)
+	if stream.Error != nil {
+		return nil, stream.Error
+	}
+	result := stream.Buffer()
+	copied := make([]byte, len(result))
+	copy(copied, result)
+	return copied, nil
+}
+
+// EncodeTo writes val followed by a newline into encoder, same as encoder.Encode(val)
+func (typed *TypedEncoder[T]) EncodeTo(encoder *Encoder, val *T) error {
+	typed.Encode(encoder.stream, val)
+	encoder.stream.WriteRaw("\n")
+	encoder.stream.Flush()
+	return encoder.stream.Error
+}
@@ -0,0 +1,57 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_nullable(t *testing.T) {
+	should := require.New(t)
+	type Patch struct {
+		Name  jsoniter.Nullable[string]   `json:"name"`
+		Age   jsoniter.Nullable[int]      `json:"age"`
+		Email jsoniter.Nullable[*string]  `json:"email,omitempty"`
+		Tags  jsoniter.Optional[[]string] `json:"tags"`
+	}
+	var patch Patch
+	should.NoError(jsoniter.UnmarshalFromString(`{"name":"a","age":null,"tags":null}`, &patch))
+	name, found := patch.Name.Get()
+	should.True(found)
+	should.Equal("a", name)
+	should.True(patch.Age.IsNull())
+	should.True(patch.Age.IsPresent())
+	should.False(patch.Email.IsPresent())
+	should.False(patch.Tags.IsSet())
+	output, err := jsoniter.MarshalToString(patch)
+	should.NoError(err)
+	should.Equal(`{"name":"a","age":null}`, output)
+
+	patch = Patch{Age: jsoniter.NewNullable(3), Email: jsoniter.Null[*string](), Tags: jsoniter.NewOptional([]string{"x"})}
+	output, err = jsoniter.MarshalToString(patch)
+	should.NoError(err)
+	should.Equal(`{"age":3,"email":null,"tags":["x"]}`, output)
+	var decoded Patch
+	should.NoError(jsoniter.UnmarshalFromString(output, &decoded))
+	should.Equal(patch, decoded)
+
+	output, err = jsoniter.MarshalToString(jsoniter.Nullable[int]{})
+	should.NoError(err)
+	should.Equal(`null`, output)
+	output, err = jsoniter.MarshalToString([]jsoniter.Nullable[int]{jsoniter.NewNullable(1), jsoniter.Null[int]()})
+	should.NoError(err)
+	should.Equal(`[1,null]`, output)
+}
+
+type recursiveNullable struct {
+	Children []recursiveNullable       `json:"children,omitempty"`
+	Name     jsoniter.Nullable[string] `json:"name"`
+}
+
+func Test_nullable_in_recursive_struct(t *testing.T) {
+	should := require.New(t)
+	output, err := jsoniter.MarshalToString(recursiveNullable{Children: []recursiveNullable{{}}})
+	should.NoError(err)
+	should.Equal(`{"children":[{}]}`, output)
+}
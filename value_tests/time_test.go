@@ -0,0 +1,101 @@
+package test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+	"unsafe"
+
+	"github.com/json-iterator/go"
+	"github.com/modern-go/reflect2"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	ts := time.Date(2018, 12, 13, 14, 15, 16, 123456789, time.UTC)
+	marshalCases = append(marshalCases,
+		ts,
+		&ts,
+		time.Time{},
+		ts.In(time.FixedZone("", -7*60*60)),
+		ts.In(time.FixedZone("", 5*60*60+30*60)),
+		struct {
+			F1 time.Time
+			F2 *time.Time
+			F3 *time.Time `json:",omitempty"`
+		}{F1: ts, F2: &ts},
+	)
+	for _, input := range []string{
+		`"2018-12-13T14:15:16Z"`,
+		`"2018-12-13T14:15:16.1Z"`,
+		`"2018-12-13T14:15:16.123456789Z"`,
+		`"2018-12-13T14:15:16.1234567891234Z"`,
+		`"2018-12-13T14:15:16+08:00"`,
+		`"2018-12-13T14:15:16.5-07:30"`,
+		`"2018-12-13T14:15:16+00:00"`,
+		`"2018-12-13T14:15:16+24:00"`,
+		`"2016-02-29T00:00:00Z"`,
+		`null`,
+	} {
+		unmarshalCases = append(unmarshalCases, unmarshalCase{
+			ptr:   (*time.Time)(nil),
+			input: input,
+		}, unmarshalCase{
+			ptr:   (**time.Time)(nil),
+			input: input,
+		})
+	}
+	unmarshalCases = append(unmarshalCases, unmarshalCase{
+		ptr:   (*struct{ F1, F2 time.Time })(nil),
+		input: `{"F1":"2018-12-13T14:15:16Z","F2":"2018-12-13T14:15:16.123+01:00"}`,
+	})
+}
+
+func Test_time_errors_same_as_std(t *testing.T) {
+	for _, input := range []string{
+		`"2017-02-29T00:00:00Z"`,
+		`"2018-13-13T14:15:16Z"`,
+		`"2018-12-13T14:15:16"`,
+		`"2018-12-13t14:15:16Z"`,
+		`"2018-12-13"`,
+	} {
+		t.Run(input, func(t *testing.T) {
+			should := require.New(t)
+			var val1, val2 time.Time
+			err1 := json.Unmarshal([]byte(input), &val1)
+			err2 := jsoniter.Unmarshal([]byte(input), &val2)
+			should.Error(err1)
+			should.Error(err2)
+			should.Contains(err2.Error(), err1.Error())
+		})
+	}
+	for _, val := range []time.Time{
+		time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2018, 1, 1, 0, 0, 0, 0, time.FixedZone("", 24*60*60)),
+	} {
+		should := require.New(t)
+		_, err1 := val.MarshalJSON()
+		_, err2 := jsoniter.Marshal(val)
+		should.Error(err1)
+		should.Equal(err1, err2)
+	}
+}
+
+func Test_time_no_allocation(t *testing.T) {
+	should := require.New(t)
+	input := []byte(`"2018-12-13T14:15:16.123456789Z"`)
+	var val time.Time
+	encoder := jsoniter.ConfigDefault.EncoderOf(reflect2.TypeOf(val))
+	iter := jsoniter.ConfigDefault.BorrowIterator(input)
+	defer jsoniter.ConfigDefault.ReturnIterator(iter)
+	stream := jsoniter.ConfigDefault.BorrowStream(nil)
+	defer jsoniter.ConfigDefault.ReturnStream(stream)
+	allocs := testing.AllocsPerRun(100, func() {
+		iter.ResetBytes(input)
+		iter.ReadVal(&val)
+		stream.Reset(nil)
+		encoder.Encode(unsafe.Pointer(&val), stream)
+	})
+	should.Equal(float64(0), allocs)
+	should.Equal(string(input), string(stream.Buffer()))
+}